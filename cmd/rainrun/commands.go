@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	rr "github.com/maseology/goHydro/rainrun"
+	"github.com/maseology/goHydro/rainrun/optimize"
+	smpl "github.com/maseology/goHydro/rainrun/sample"
+	"github.com/maseology/goHydro/rainrun/validate"
+	"github.com/maseology/mmio"
+)
+
+// run simulates a parameter set, writing the outputs of rainrun.EvalPNG
+func run(args []string) error {
+	o := newOptions("run").withParams()
+	frc, err := o.parse(args)
+	if err != nil {
+		return err
+	}
+	p, err := o.parameters(frc)
+	if err != nil {
+		return err
+	}
+	m, err := rr.NewModelAt(o.model, frc.Timestep, p...)
+	if err != nil {
+		return err
+	}
+	fmt.Printf(" %s parameters: %.4g\n", o.model, p)
+	_, err = rr.EvalPNG(m, frc, o.warmup, o.prefix(frc))
+	return err
+}
+
+// calibrate optimizes a model, saving the optimal parameters to <prefix>.par
+func calibrate(args []string) error {
+	o := newOptions("calibrate")
+	pareto := o.fs.Bool("pareto", false, "seek the Pareto front of multiple objectives using NSGA-II (default: minimize their weighted sum using SCE)")
+	frc, err := o.parse(args)
+	if err != nil {
+		return err
+	}
+	objs, err := o.objectives()
+	if err != nil {
+		return err
+	}
+	cfg := optimize.Config{Model: o.model, Objectives: objs, Warmup: o.warmup, Seed: o.seed}
+	if *pareto {
+		cfg.Mode = optimize.Pareto
+	}
+	res, err := optimize.OptimizeConfig(frc, cfg)
+	if err != nil {
+		return err
+	}
+	return writeParams(o.prefix(frc)+".par", res.Best.P)
+}
+
+// sample evaluates a Monte Carlo sample of the parameter space, saving <prefix>.samples.csv
+func sample(args []string) error {
+	o := newOptions("sample")
+	n := o.fs.Int("n", 10000, "number of samples")
+	frc, err := o.parse(args)
+	if err != nil {
+		return err
+	}
+	objs, err := o.objectives()
+	if err != nil {
+		return err
+	}
+	if len(objs) > 1 {
+		return fmt.Errorf("a single objective is sampled")
+	}
+	sp, err := smpl.Get(o.model)
+	if err != nil {
+		return err
+	}
+	mi, _ := rr.Lookup(o.model)
+
+	fmt.Printf(" sampling %s %d times..\n", o.model, *n)
+	u, f, seed, err := smpl.SampleSeed(*frc, o.model, *n, o.warmup, o.seed, objs[0].F)
+	if err != nil {
+		return err
+	}
+	cols := make([][]interface{}, mi.Ndim()+1)
+	for j := range cols {
+		cols[j] = make([]interface{}, len(u))
+	}
+	for i := range u {
+		cols[0][i] = f[i]
+		for j, v := range sp(u[i], frc.Timestep) {
+			cols[j+1][i] = v
+		}
+	}
+	prfx := o.prefix(frc)
+	fp := prfx + ".samples.csv"
+	mmio.WriteCSV(fp, objs[0].Name+","+strings.Join(mi.ParamNames(), ","), cols...)
+	fmt.Printf(" %d samples written to %s (seed: %d)\n", len(u), fp, seed)
+	logger := mmio.GetInstance(prfx + ".log")
+	logger.Println(mmio.FileName(frc.FilePath, false))
+	logger.Printf("%s: %d samples of %s following a %d timestep warm-up, seed: %d\n", fp, len(u), objs[0].Name, o.warmup, seed)
+	return nil
+}
+
+// evaluate scores a parameter set per period, season and year, writing the
+// outputs of validate.Report.Write and rainrun.EvalPNG
+func evaluate(args []string) error {
+	o := newOptions("evaluate").withParams()
+	frc, err := o.parse(args)
+	if err != nil {
+		return err
+	}
+	p, err := o.parameters(frc)
+	if err != nil {
+		return err
+	}
+	objs, err := o.objectives()
+	if err != nil {
+		return err
+	}
+	r, err := validate.Validate(frc, o.model, p, o.warmup, objs)
+	if err != nil {
+		return err
+	}
+	fmt.Print(r)
+	prfx := o.prefix(frc)
+	r.Write(prfx)
+
+	m, err := rr.NewModelAt(o.model, frc.Timestep, p...)
+	if err != nil {
+		return err
+	}
+	_, err = rr.EvalPNG(m, frc, o.warmup, prfx)
+	return err
+}
+
+func writeParams(fp string, p []float64) error {
+	s := make([]string, len(p))
+	for i, v := range p {
+		s[i] = fmt.Sprintf("%g", v)
+	}
+	if err := os.WriteFile(fp, []byte(strings.Join(s, ",")+"\n"), 0644); err != nil {
+		return err
+	}
+	fmt.Printf(" parameters written to %s\n", fp)
+	return nil
+}
@@ -0,0 +1,72 @@
+// Command rainrun runs, calibrates, samples and evaluates the registered
+// rainfall-runoff models of github.com/maseology/goHydro/rainrun on a forcing file.
+//
+// Usage:
+//
+//	rainrun <command> [options] <forcing file>
+//
+// Commands:
+//
+//	run        simulate a parameter set (default: registered defaults)
+//	calibrate  calibrate a model, saving the optimal parameters to <prefix>.par
+//	sample     Monte Carlo sample the parameter space, saving <prefix>.samples.csv
+//	evaluate   score a parameter set per period, season and year (see rainrun/validate)
+//	models     list the registered models, snowpacks and PET estimators
+//
+// Outputs are written alongside the forcing file, prefixed by the file name and
+// model (e.g., gauge.GR4J.hyd.png), as produced by rainrun.EvalPNG.
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	rr "github.com/maseology/goHydro/rainrun"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "run":
+		err = run(os.Args[2:])
+	case "calibrate":
+		err = calibrate(os.Args[2:])
+	case "sample":
+		err = sample(os.Args[2:])
+	case "evaluate":
+		err = evaluate(os.Args[2:])
+	case "models":
+		fmt.Printf("models:   %s\n", strings.Join(rr.Models(), ", "))
+		fmt.Printf("snowpack: %s\n", strings.Join(rr.Snowpacks(), ", "))
+		fmt.Printf("PET:      %s\n", strings.Join(rr.PETs(), ", "))
+	case "-h", "-help", "--help", "help":
+		usage()
+	default:
+		fmt.Fprintf(os.Stderr, "rainrun: unknown command: %s\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "rainrun %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprint(os.Stderr, `usage: rainrun <command> [options] <forcing file>
+
+commands:
+  run        simulate a parameter set (default: registered defaults)
+  calibrate  calibrate a model, saving the optimal parameters to <prefix>.par
+  sample     Monte Carlo sample the parameter space
+  evaluate   score a parameter set per period, season and year
+  models     list the registered models, snowpacks and PET estimators
+
+run "rainrun <command> -h" for the options of a command
+`)
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	rr "github.com/maseology/goHydro/rainrun"
+	"github.com/maseology/goHydro/rainrun/optimize"
+	"github.com/maseology/mmio"
+)
+
+// options common to all commands
+type options struct {
+	fs *flag.FlagSet
+
+	model, format, columns, layout, flowfp string
+	cakm2, lat, elev, ts, depth            float64
+	loc                                    int
+
+	obj, start, end, params, pfile string
+	warmup                         int
+	seed                           int64
+}
+
+func newOptions(cmd string) *options {
+	o := options{fs: flag.NewFlagSet(cmd, flag.ExitOnError)}
+	o.fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: rainrun %s [options] <forcing file>\n\noptions:\n", cmd)
+		o.fs.PrintDefaults()
+	}
+	o.fs.StringVar(&o.model, "model", "GR4J", "registered model, optionally coupled (e.g., GR4J+CCF+Makkink); see \"rainrun models\"")
+	o.fs.StringVar(&o.format, "format", "", "forcing file format: owrc, csv, camels or met (default: met for *.met, otherwise owrc)")
+	o.fs.StringVar(&o.columns, "columns", "", "csv format: zero-based column of each variable, e.g. date=0,flow=1,tx=2,tn=3,precip=4 (variables: date, flow, tx, tn, tm, precip, rain, snow, melt, pa, pet, kg)")
+	o.fs.StringVar(&o.layout, "layout", "", "date layout (default 2006-01-02, or 2006-01-02 15:04 when -timestep < 86400)")
+	o.fs.StringVar(&o.flowfp, "flow", "", "camels format: streamflow file (*_streamflow_qc.txt)")
+	o.fs.Float64Var(&o.cakm2, "area", 0., "catchment area [km²], converting flows from m³/s (owrc and csv formats)")
+	o.fs.Float64Var(&o.lat, "lat", 45., "catchment latitude [°]")
+	o.fs.Float64Var(&o.elev, "elev", 0., "catchment elevation [m]")
+	o.fs.Float64Var(&o.ts, "timestep", 86400., "forcing timestep [s] (owrc and csv formats)")
+	o.fs.Float64Var(&o.depth, "depth", 1., "factor converting forcing depths to mm (e.g., 1000 when in metres)")
+	o.fs.IntVar(&o.loc, "loc", 0, "met format: zero-based location index")
+	o.fs.StringVar(&o.obj, "obj", "NSE", "objective(s), optionally weighted, e.g. NSE or KGE:0.7,logNSE:0.3 (NSE, KGE, logNSE, bias, FDC)")
+	o.fs.StringVar(&o.start, "start", "", "first date simulated (YYYY-MM-DD), warm-up included (default: start of record)")
+	o.fs.StringVar(&o.end, "end", "", "last date simulated (YYYY-MM-DD) (default: end of record)")
+	o.fs.IntVar(&o.warmup, "warmup", -1, "number of warm-up timesteps excluded from scoring (default: 1 year)")
+	o.fs.Int64Var(&o.seed, "seed", 0, "random seed of calibrate and sample, repeating a run (default: seeded from the clock, the seed used is logged)")
+	return &o
+}
+
+// withParams adds the parameter options of the run and evaluate commands
+func (o *options) withParams() *options {
+	o.fs.StringVar(&o.params, "p", "", "comma-separated parameter values, at the forcing timestep (default: registered defaults)")
+	o.fs.StringVar(&o.pfile, "pfile", "", "file of parameter values (e.g., <prefix>.par written by calibrate)")
+	return o
+}
+
+// parse the command-line, returning the forcing data
+func (o *options) parse(args []string) (*rr.Frc, error) {
+	o.fs.Parse(args)
+	if o.fs.NArg() != 1 {
+		o.fs.Usage()
+		return nil, fmt.Errorf("a single forcing file is required")
+	}
+	if _, ok := rr.Lookup(o.model); !ok {
+		return nil, fmt.Errorf("unrecognized model: %s", o.model)
+	}
+	fp := o.fs.Arg(0)
+	frc, err := o.load(fp)
+	if err != nil {
+		return nil, err
+	}
+	if frc, err = subset(frc, o.start, o.end); err != nil {
+		return nil, err
+	}
+	if n := frc.FillGaps(); n > 0 {
+		fmt.Printf(" %d gaps in forcing data filled\n", n)
+	}
+	if o.warmup < 0 {
+		o.warmup = frc.Year()
+	}
+	if o.warmup >= frc.Ndt {
+		return nil, fmt.Errorf("warm-up of %d exceeds the %d timesteps simulated", o.warmup, frc.Ndt)
+	}
+	fmt.Printf(" %s: %d timesteps (%.0fs), %d warm-up, scored on %v\n", fp, frc.Ndt, frc.Timestep, o.warmup, frc.Mask()[o.warmup:])
+	return frc, nil
+}
+
+func (o *options) load(fp string) (*rr.Frc, error) {
+	ld := rr.Loader{Layout: o.layout, Timestep: o.ts, Depth: o.depth, Cakm2: o.cakm2, Latitude: o.lat, Elevation: o.elev}
+	if ld.Layout == "" && o.ts < 86400. {
+		ld.Layout = "2006-01-02 15:04"
+	}
+	format := o.format
+	if format == "" {
+		format = "owrc"
+		if strings.ToLower(filepath.Ext(fp)) == ".met" {
+			format = "met"
+		}
+	}
+	switch format {
+	case "owrc":
+		ow := rr.OWRC(o.cakm2, o.lat)
+		ld.Columns, ld.Skip = ow.Columns, ow.Skip
+		return ld.Load(fp)
+	case "csv":
+		cols, err := parseColumns(o.columns)
+		if err != nil {
+			return nil, err
+		}
+		ld.Columns, ld.Skip = cols, 1
+		return ld.Load(fp)
+	case "camels":
+		return ld.LoadCAMELS(fp, o.flowfp)
+	case "met":
+		return ld.LoadMET(fp, o.loc)
+	}
+	return nil, fmt.Errorf("unknown forcing format: %s", format)
+}
+
+var columnNames = map[string]rr.Column{
+	"date":   rr.ColDate,
+	"flow":   rr.ColFlow,
+	"tx":     rr.ColTx,
+	"tn":     rr.ColTn,
+	"tm":     rr.ColTm,
+	"precip": rr.ColPrecip,
+	"rain":   rr.ColRain,
+	"snow":   rr.ColSnow,
+	"melt":   rr.ColMelt,
+	"pa":     rr.ColPa,
+	"pet":    rr.ColPET,
+	"kg":     rr.ColKg,
+}
+
+func parseColumns(s string) (map[rr.Column]int, error) {
+	if s == "" {
+		return nil, fmt.Errorf("csv format requires -columns")
+	}
+	m := make(map[rr.Column]int)
+	for _, kv := range strings.Split(s, ",") {
+		sp := strings.Split(kv, "=")
+		if len(sp) != 2 {
+			return nil, fmt.Errorf("invalid column mapping: %s", kv)
+		}
+		c, ok := columnNames[strings.ToLower(strings.TrimSpace(sp[0]))]
+		if !ok {
+			return nil, fmt.Errorf("unknown column variable: %s", sp[0])
+		}
+		i, err := strconv.Atoi(strings.TrimSpace(sp[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid column index: %s", kv)
+		}
+		m[c] = i
+	}
+	return m, nil
+}
+
+// subset trims the forcing record to dates [start, end]
+func subset(frc *rr.Frc, start, end string) (*rr.Frc, error) {
+	if start == "" && end == "" {
+		return frc, nil
+	}
+	if len(frc.DT) != len(frc.D) {
+		return nil, fmt.Errorf("forcing dates required to set the period")
+	}
+	t0, t1 := frc.DT[0], frc.DT[len(frc.DT)-1]
+	var err error
+	if start != "" {
+		if t0, err = time.Parse("2006-01-02", start); err != nil {
+			return nil, fmt.Errorf("-start: %v", err)
+		}
+	}
+	if end != "" {
+		if t1, err = time.Parse("2006-01-02", end); err != nil {
+			return nil, fmt.Errorf("-end: %v", err)
+		}
+		t1 = t1.Add(24*time.Hour - time.Nanosecond) // inclusive
+	}
+	i0, i1 := -1, -1
+	for i, t := range frc.DT {
+		if !t.Before(t0) && !t.After(t1) {
+			if i0 < 0 {
+				i0 = i
+			}
+			i1 = i + 1
+		}
+	}
+	if i0 < 0 {
+		return nil, fmt.Errorf("no forcing data within %s to %s", t0.Format("2006-01-02"), t1.Format("2006-01-02"))
+	}
+	sub := *frc
+	sub.D, sub.DT, sub.Ndt = frc.D[i0:i1], frc.DT[i0:i1], i1-i0
+	return &sub, nil
+}
+
+// objectives parses the -obj option
+func (o *options) objectives() ([]optimize.Objective, error) {
+	var objs []optimize.Objective
+	for _, s := range strings.Split(o.obj, ",") {
+		sp := strings.Split(strings.TrimSpace(s), ":")
+		w := 1.
+		if len(sp) == 2 {
+			var err error
+			if w, err = strconv.ParseFloat(sp[1], 64); err != nil {
+				return nil, fmt.Errorf("invalid objective weight: %s", s)
+			}
+		}
+		obj, err := optimize.ObjectiveByName(sp[0], w)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// parameters returns the parameter set given by -p or -pfile, or the registered defaults
+func (o *options) parameters(frc *rr.Frc) ([]float64, error) {
+	mi, _ := rr.Lookup(o.model)
+	s := o.params
+	if o.pfile != "" {
+		b, err := os.ReadFile(o.pfile)
+		if err != nil {
+			return nil, err
+		}
+		s = string(b)
+	}
+	if strings.TrimSpace(s) == "" {
+		return mi.Defaults(frc.Timestep), nil
+	}
+	var p []float64
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r' }) {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parameter value: %s", f)
+		}
+		p = append(p, v)
+	}
+	if len(p) != mi.Ndim() {
+		return nil, fmt.Errorf("%s: %d parameters given, %d expected (%s)", o.model, len(p), mi.Ndim(), strings.Join(mi.ParamNames(), ", "))
+	}
+	return p, nil
+}
+
+// prefix of output files
+func (o *options) prefix(frc *rr.Frc) string {
+	return mmio.RemoveExtension(frc.FilePath) + "." + o.model
+}
@@ -1,5 +1,7 @@
 package convolution
 
+import "fmt"
+
 type Convolution struct{ w, q []float64 }
 
 func (cv *Convolution) Update(qIn float64) float64 {
@@ -18,3 +20,17 @@ func (cv *Convolution) Update(qIn float64) float64 {
 func (cv *Convolution) Weights() []float64 {
 	return cv.w
 }
+
+// State returns a copy of the convolution store
+func (cv *Convolution) State() []float64 {
+	return append([]float64(nil), cv.q...)
+}
+
+// SetState overwrites the convolution store
+func (cv *Convolution) SetState(q []float64) error {
+	if len(q) != len(cv.q) {
+		return fmt.Errorf("Convolution.SetState: state length %d, expecting %d", len(q), len(cv.q))
+	}
+	copy(cv.q, q)
+	return nil
+}
@@ -0,0 +1,41 @@
+package convolution
+
+import "math"
+
+// SCS dimensionless unit hydrograph (t/Tp, q/qp)
+// ref: USDA-NRCS, 2007. National Engineering Handbook, Part 630, Chapter 16, Table 16-1.
+var scsDimensionless = [][2]float64{
+	{0, 0}, {.1, .030}, {.2, .100}, {.3, .190}, {.4, .310}, {.5, .470}, {.6, .660}, {.7, .820}, {.8, .930}, {.9, .990},
+	{1.0, 1.000}, {1.1, .990}, {1.2, .930}, {1.3, .860}, {1.4, .780}, {1.5, .680}, {1.6, .560}, {1.8, .390}, {2.0, .280},
+	{2.2, .207}, {2.4, .147}, {2.6, .107}, {2.8, .077}, {3.0, .055}, {3.2, .040}, {3.4, .029}, {3.6, .021}, {3.8, .015},
+	{4.0, .011}, {4.5, .005}, {5.0, 0},
+}
+
+// SCS returns the normalized ordinates of the SCS dimensionless unit hydrograph,
+// given the basin lag [hr]; time to peak Tp = ts/2 + lag (as in HEC-HMS)
+func SCS(lag, tsminutes float64) []float64 {
+	tshr := tsminutes / 60.
+	tp := tshr/2 + lag
+	nstep := int(math.Ceil(5 * tp / tshr))
+	if nstep <= 1 {
+		return []float64{1.}
+	}
+	interp := func(x float64) float64 {
+		for i := 1; i < len(scsDimensionless); i++ {
+			if x <= scsDimensionless[i][0] {
+				p0, p1 := scsDimensionless[i-1], scsDimensionless[i]
+				return p0[1] + (x-p0[0])*(p1[1]-p0[1])/(p1[0]-p0[0])
+			}
+		}
+		return 0.
+	}
+	ords, s := make([]float64, nstep), 0.
+	for i := range nstep {
+		ords[i] = interp((float64(i) + .5) * tshr / tp) // midpoint
+		s += ords[i]
+	}
+	for i := range nstep {
+		ords[i] /= s // normalize
+	}
+	return ords
+}
@@ -0,0 +1,164 @@
+package forcing
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoadCSV reads a csv file of one row per location and timestep, headed by
+// the column names "date", "location", "precip" and optionally "temp" and
+// "pet" (in any order, case-insensitive). Dates are parsed using layout
+// ("2006-01-02" when blank); blank, NA, NaN and -9999 values are read as NaN,
+// as are timesteps missing at a location. Locations are ordered as they first
+// appear.
+func LoadCSV(fp, layout string) (*Forcing, error) {
+	f, err := os.Open(fp)
+	if err != nil {
+		return nil, fmt.Errorf("forcing.LoadCSV: %v", err)
+	}
+	defer f.Close()
+	frc, err := ReadCSV(f, layout)
+	if err != nil {
+		return nil, fmt.Errorf("forcing.LoadCSV %s: %v", fp, err)
+	}
+	return frc, nil
+}
+
+// ReadCSV reads csv-formatted forcing data, see LoadCSV
+func ReadCSV(r io.Reader, layout string) (*Forcing, error) {
+	if layout == "" {
+		layout = "2006-01-02"
+	}
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	hdr, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("header: %v", err)
+	}
+	col := map[string]int{"date": -1, "location": -1, "precip": -1, "temp": -1, "pet": -1}
+	for i, h := range hdr {
+		if _, ok := col[strings.ToLower(strings.TrimSpace(h))]; ok {
+			col[strings.ToLower(strings.TrimSpace(h))] = i
+		}
+	}
+	for _, c := range []string{"date", "location", "precip"} {
+		if col[c] < 0 {
+			return nil, fmt.Errorf("header: %s column required", c)
+		}
+	}
+	parse := func(s string) (float64, error) {
+		switch s = strings.TrimSpace(s); s {
+		case "", "NA", "NaN", "-9999":
+			return math.NaN(), nil
+		}
+		return strconv.ParseFloat(s, 64)
+	}
+
+	type rec struct{ p, t, e float64 }
+	dat := make(map[int]map[time.Time]rec)
+	tset := make(map[time.Time]bool)
+	var lids []int
+	for ln := 2; ; ln++ {
+		ss, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		get := func(c string) (float64, error) {
+			if col[c] < 0 {
+				return math.NaN(), nil
+			}
+			if col[c] >= len(ss) {
+				return 0, fmt.Errorf("line %d: %s column %d out of range", ln, c, col[c])
+			}
+			v, err := parse(ss[col[c]])
+			if err != nil {
+				return 0, fmt.Errorf("line %d, %s: %v", ln, c, err)
+			}
+			return v, nil
+		}
+		if col["date"] >= len(ss) || col["location"] >= len(ss) {
+			return nil, fmt.Errorf("line %d: %d fields", ln, len(ss))
+		}
+		t, err := time.Parse(layout, strings.TrimSpace(ss[col["date"]]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", ln, err)
+		}
+		lid, err := strconv.Atoi(strings.TrimSpace(ss[col["location"]]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: location: %v", ln, err)
+		}
+		var v rec
+		if v.p, err = get("precip"); err != nil {
+			return nil, err
+		}
+		if v.t, err = get("temp"); err != nil {
+			return nil, err
+		}
+		if v.e, err = get("pet"); err != nil {
+			return nil, err
+		}
+		if _, ok := dat[lid]; !ok {
+			dat[lid] = make(map[time.Time]rec)
+			lids = append(lids, lid)
+		}
+		if _, ok := dat[lid][t]; ok {
+			return nil, fmt.Errorf("line %d: location %d repeats %v", ln, lid, t)
+		}
+		dat[lid][t] = v
+		tset[t] = true
+	}
+	if len(tset) == 0 {
+		return nil, fmt.Errorf("no data read")
+	}
+
+	frc := Forcing{T: make([]time.Time, 0, len(tset)), Lid: lids, Ya: make([][]float64, len(lids))}
+	for t := range tset {
+		frc.T = append(frc.T, t)
+	}
+	sort.Slice(frc.T, func(i, j int) bool { return frc.T[i].Before(frc.T[j]) })
+	frc.IntervalSec = 86400.
+	if len(frc.T) > 1 {
+		frc.IntervalSec = frc.T[1].Sub(frc.T[0]).Seconds()
+	}
+	if col["temp"] >= 0 {
+		frc.Ta = make([][]float64, len(lids))
+	}
+	if col["pet"] >= 0 {
+		frc.Ea = make([][]float64, len(lids))
+	}
+	for i, lid := range lids {
+		frc.Ya[i] = nans(len(frc.T))
+		if frc.Ta != nil {
+			frc.Ta[i] = nans(len(frc.T))
+		}
+		if frc.Ea != nil {
+			frc.Ea[i] = nans(len(frc.T))
+		}
+		for j, t := range frc.T {
+			if v, ok := dat[lid][t]; ok {
+				frc.Ya[i][j] = v.p
+				if frc.Ta != nil {
+					frc.Ta[i][j] = v.t
+				}
+				if frc.Ea != nil {
+					frc.Ea[i][j] = v.e
+				}
+			}
+		}
+	}
+	if err := frc.Check(); err != nil {
+		return nil, err
+	}
+	return &frc, nil
+}
@@ -0,0 +1,82 @@
+// Package forcing holds time-indexed climate forcings (precipitation,
+// temperature and potential evaporation) at a set of locations, be they
+// stations, subbasins or cells. It is the common input of hechms, swat and
+// rainrun.
+package forcing
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Forcing climate data at a set of locations; all series are indexed [location][timestep]
+type Forcing struct {
+	T           []time.Time // timesteps
+	Ya          [][]float64 // precipitation [mm/ts]
+	Ta          [][]float64 // mean air temperature [°C], nil when absent
+	Ea          [][]float64 // potential evaporation [mm/ts], nil when absent
+	Lid         []int       // location IDs
+	IntervalSec float64     // timestep [s]
+}
+
+// Nloc returns the number of locations
+func (f *Forcing) Nloc() int { return len(f.Lid) }
+
+// Nstep returns the number of timesteps
+func (f *Forcing) Nstep() int { return len(f.T) }
+
+// Index returns the (zero-based) index of location ID lid
+func (f *Forcing) Index(lid int) (int, bool) {
+	for i, l := range f.Lid {
+		if l == lid {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// Check returns an error when series do not match the locations and
+// timesteps, or the timesteps are not at a regular interval
+func (f *Forcing) Check() error {
+	if f.IntervalSec <= 0 {
+		return fmt.Errorf("forcing: interval must be greater than zero, %g given", f.IntervalSec)
+	}
+	if len(f.T) == 0 {
+		return fmt.Errorf("forcing: no timesteps")
+	}
+	for i := 1; i < len(f.T); i++ {
+		if f.T[i].Sub(f.T[i-1]).Seconds() != f.IntervalSec {
+			return fmt.Errorf("forcing: irregular timestep between %v and %v, expecting %gs", f.T[i-1], f.T[i], f.IntervalSec)
+		}
+	}
+	chk := func(name string, a [][]float64, required bool) error {
+		if a == nil && !required {
+			return nil
+		}
+		if len(a) != len(f.Lid) {
+			return fmt.Errorf("forcing: %s given at %d locations, expecting %d", name, len(a), len(f.Lid))
+		}
+		for i, s := range a {
+			if len(s) != len(f.T) {
+				return fmt.Errorf("forcing: %s at location %d has %d timesteps, expecting %d", name, f.Lid[i], len(s), len(f.T))
+			}
+		}
+		return nil
+	}
+	if err := chk("precipitation", f.Ya, true); err != nil {
+		return err
+	}
+	if err := chk("temperature", f.Ta, false); err != nil {
+		return err
+	}
+	return chk("potential evaporation", f.Ea, false)
+}
+
+func nans(n int) []float64 {
+	s := make([]float64, n)
+	for i := range s {
+		s[i] = math.NaN()
+	}
+	return s
+}
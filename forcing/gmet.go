@@ -0,0 +1,56 @@
+package forcing
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/maseology/goHydro/gmet"
+)
+
+// LoadGMET reads a gob-encoded gmet.GMET (see gmet.LoadGob), see FromGMET
+func LoadGMET(fp, precip, temp, pet string) (*Forcing, error) {
+	g, err := gmet.LoadGob(fp)
+	if err != nil {
+		return nil, fmt.Errorf("forcing.LoadGMET: %v", err)
+	}
+	return FromGMET(g, precip, temp, pet)
+}
+
+// FromGMET returns the forcings of a gmet.GMET given the names of its
+// precipitation, temperature and potential evaporation variables (temp and pet
+// may be left blank when absent). Location IDs are the GMET station IDs.
+func FromGMET(g *gmet.GMET, precip, temp, pet string) (*Forcing, error) {
+	if g.Nsta == 0 || len(g.Ts) == 0 {
+		return nil, fmt.Errorf("forcing.FromGMET: no data")
+	}
+	get := func(name string) ([][]float64, error) {
+		if name == "" {
+			return nil, nil
+		}
+		if !slices.Contains(g.Snams, name) {
+			return nil, fmt.Errorf("forcing.FromGMET: variable %s not found among %v", name, g.Snams)
+		}
+		return g.GetAllData(name), nil
+	}
+	if precip == "" {
+		return nil, fmt.Errorf("forcing.FromGMET: a precipitation variable is required")
+	}
+	frc := Forcing{T: g.Ts, Lid: g.Sids, IntervalSec: 86400.}
+	if len(g.Ts) > 1 {
+		frc.IntervalSec = g.Ts[1].Sub(g.Ts[0]).Seconds()
+	}
+	var err error
+	if frc.Ya, err = get(precip); err != nil {
+		return nil, err
+	}
+	if frc.Ta, err = get(temp); err != nil {
+		return nil, err
+	}
+	if frc.Ea, err = get(pet); err != nil {
+		return nil, err
+	}
+	if err := frc.Check(); err != nil {
+		return nil, fmt.Errorf("forcing.FromGMET: %v", err)
+	}
+	return &frc, nil
+}
@@ -0,0 +1,70 @@
+package forcing
+
+import (
+	"fmt"
+
+	"github.com/maseology/goHydro/met"
+)
+
+// LoadMET reads a .met file. Precipitation is read from Precipitation, else
+// Rainfall + Snowfall, else AtmosphericYield; temperature from Temperature,
+// else the mean of MaxDailyT and MinDailyT; potential evaporation from
+// AtmosphericDemand. Location IDs are the zero-based location index of the file.
+func LoadMET(fp string) (*Forcing, error) {
+	h, c, err := met.ReadMET(fp, false)
+	if err != nil {
+		return nil, fmt.Errorf("forcing.LoadMET: %v", err)
+	}
+	if h.IntervalSec() <= 0 {
+		return nil, fmt.Errorf("forcing.LoadMET %s: a fixed timestep interval is required", fp)
+	}
+	xr, nloc, nt := h.WBDCxr(), h.Nloc(), len(c.T)
+	has := func(names ...string) bool {
+		for _, n := range names {
+			if _, ok := xr[n]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+	series := func(fn func(d []float64) float64) [][]float64 {
+		a := make([][]float64, nloc)
+		for i := range nloc {
+			a[i] = make([]float64, nt)
+			for j := range nt {
+				a[i][j] = fn(c.D[j][i])
+			}
+		}
+		return a
+	}
+
+	frc := Forcing{T: c.T, Lid: make([]int, nloc), IntervalSec: h.IntervalSec()}
+	for i := range nloc {
+		frc.Lid[i] = i
+	}
+	switch {
+	case has("Precipitation"):
+		frc.Ya = series(func(d []float64) float64 { return d[xr["Precipitation"]] })
+	case has("Rainfall", "Snowfall"):
+		frc.Ya = series(func(d []float64) float64 { return d[xr["Rainfall"]] + d[xr["Snowfall"]] })
+	case has("Rainfall"):
+		frc.Ya = series(func(d []float64) float64 { return d[xr["Rainfall"]] })
+	case has("AtmosphericYield"):
+		frc.Ya = series(func(d []float64) float64 { return d[xr["AtmosphericYield"]] })
+	default:
+		return nil, fmt.Errorf("forcing.LoadMET %s: no precipitation found among %v", fp, h.WBlist())
+	}
+	switch {
+	case has("Temperature"):
+		frc.Ta = series(func(d []float64) float64 { return d[xr["Temperature"]] })
+	case has("MaxDailyT", "MinDailyT"):
+		frc.Ta = series(func(d []float64) float64 { return (d[xr["MaxDailyT"]] + d[xr["MinDailyT"]]) / 2 })
+	}
+	if has("AtmosphericDemand") {
+		frc.Ea = series(func(d []float64) float64 { return d[xr["AtmosphericDemand"]] })
+	}
+	if err := frc.Check(); err != nil {
+		return nil, fmt.Errorf("forcing.LoadMET %s: %v", fp, err)
+	}
+	return &frc, nil
+}
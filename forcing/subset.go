@@ -0,0 +1,73 @@
+package forcing
+
+import (
+	"fmt"
+	"time"
+)
+
+// Subset returns the forcings of timesteps within [dtb, dte]; series are shared with f
+func (f *Forcing) Subset(dtb, dte time.Time) (*Forcing, error) {
+	i0, i1 := -1, -1
+	for i, t := range f.T {
+		if !t.Before(dtb) && !t.After(dte) {
+			if i0 < 0 {
+				i0 = i
+			}
+			i1 = i + 1
+		}
+	}
+	if i0 < 0 {
+		return nil, fmt.Errorf("forcing.Subset: no timesteps within %v to %v", dtb, dte)
+	}
+	slc := func(a [][]float64) [][]float64 {
+		if a == nil {
+			return nil
+		}
+		o := make([][]float64, len(a))
+		for i, s := range a {
+			o[i] = s[i0:i1]
+		}
+		return o
+	}
+	return &Forcing{
+		T:           f.T[i0:i1],
+		Ya:          slc(f.Ya),
+		Ta:          slc(f.Ta),
+		Ea:          slc(f.Ea),
+		Lid:         f.Lid,
+		IntervalSec: f.IntervalSec,
+	}, nil
+}
+
+// Locations returns the forcings of the given location IDs, in the order given; series are shared with f
+func (f *Forcing) Locations(lids ...int) (*Forcing, error) {
+	if len(lids) == 0 {
+		return nil, fmt.Errorf("forcing.Locations: no locations given")
+	}
+	ii := make([]int, len(lids))
+	for j, l := range lids {
+		i, ok := f.Index(l)
+		if !ok {
+			return nil, fmt.Errorf("forcing.Locations: location %d not found", l)
+		}
+		ii[j] = i
+	}
+	sel := func(a [][]float64) [][]float64 {
+		if a == nil {
+			return nil
+		}
+		o := make([][]float64, len(ii))
+		for j, i := range ii {
+			o[j] = a[i]
+		}
+		return o
+	}
+	return &Forcing{
+		T:           f.T,
+		Ya:          sel(f.Ya),
+		Ta:          sel(f.Ta),
+		Ea:          sel(f.Ea),
+		Lid:         append([]int(nil), lids...),
+		IntervalSec: f.IntervalSec,
+	}, nil
+}
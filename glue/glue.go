@@ -0,0 +1,168 @@
+package glue
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// GLUE : Generalized Likelihood Uncertainty Estimator
+// Beven, K.J. and A.M. Binley, 1992. The future of distributed models: model calibration and uncertainty prediction. Hydrological Processes 6. pp. 279-298.
+// Samples are never reordered (sorting-safe): ordering is held as a set of indices such that U[i] always corresponds to L[i].
+type GLUE struct {
+	U   [][]float64 // parameter samples (e.g., unit hypercube samples returned from sample.Sample)
+	L   []float64   // likelihood measure of each sample (e.g., NSE), larger is better
+	ord []int       // sample indices sorted by decreasing likelihood
+	beh []int       // behavioural sample indices, sorted by decreasing likelihood
+	w   []float64   // normalized likelihood weights, ordered as beh
+}
+
+// New GLUE constructor
+func New(u [][]float64, l []float64) (*GLUE, error) {
+	if len(u) != len(l) {
+		return nil, fmt.Errorf("glue.New: %d samples given with %d likelihoods", len(u), len(l))
+	}
+	if len(u) == 0 {
+		return nil, fmt.Errorf("glue.New: no samples given")
+	}
+	g := GLUE{U: u, L: l, ord: make([]int, len(l))}
+	for i := range g.ord {
+		g.ord[i] = i
+	}
+	sort.SliceStable(g.ord, func(a, b int) bool { return less(l[g.ord[b]], l[g.ord[a]]) })
+	return &g, nil
+}
+
+func less(a, b float64) bool { // NaNs sorted last
+	if math.IsNaN(a) {
+		return !math.IsNaN(b)
+	}
+	return a < b
+}
+
+// Behavioural retains samples with likelihoods greater than the threshold,
+// weighted by (L-threshold)^shape (shape=1 weights linearly; larger values
+// emphasize the best samples). Returns the number of behavioural samples.
+func (g *GLUE) Behavioural(threshold, shape float64) (int, error) {
+	g.beh, g.w = nil, nil
+	for _, i := range g.ord {
+		if math.IsNaN(g.L[i]) || g.L[i] <= threshold {
+			break
+		}
+		g.beh = append(g.beh, i)
+	}
+	if len(g.beh) == 0 {
+		return 0, fmt.Errorf("glue.Behavioural: no samples found above the threshold of %f", threshold)
+	}
+	g.w = make([]float64, len(g.beh))
+	sw := 0.
+	for k, i := range g.beh {
+		g.w[k] = math.Pow(g.L[i]-threshold, shape)
+		sw += g.w[k]
+	}
+	for k := range g.w {
+		g.w[k] /= sw
+	}
+	return len(g.beh), nil
+}
+
+// Best returns the index of the sample of greatest likelihood
+func (g *GLUE) Best() int { return g.ord[0] }
+
+// Samples returns the behavioural sample indices, sorted by decreasing likelihood, and their normalized weights
+func (g *GLUE) Samples() ([]int, []float64) { return g.beh, g.w }
+
+// Marginal is the posterior distribution of a single parameter
+type Marginal struct {
+	X, CDF              []float64 // sorted parameter values and their cumulative weight
+	Mean, P05, P50, P95 float64
+}
+
+// Quantile returns the parameter value at cumulative probability p
+func (m *Marginal) Quantile(p float64) float64 { return quantile(m.X, m.CDF, p) }
+
+// Posterior returns the likelihood-weighted marginal distribution of each
+// parameter of the behavioural set. Samples are transformed to parameter
+// space using f (e.g., a rainrun/sample Sampler); when nil, U is used as given.
+func (g *GLUE) Posterior(f func(u []float64) []float64) ([]Marginal, error) {
+	if len(g.beh) == 0 {
+		return nil, fmt.Errorf("glue.Posterior: behavioural set not defined")
+	}
+	p := make([][]float64, len(g.beh))
+	for k, i := range g.beh {
+		if f == nil {
+			p[k] = g.U[i]
+		} else {
+			p[k] = f(g.U[i])
+		}
+	}
+	m := make([]Marginal, len(p[0]))
+	for j := range m {
+		x := make([]float64, len(p))
+		for k := range p {
+			x[k] = p[k][j]
+		}
+		m[j].X, m[j].CDF = cdf(x, g.w)
+		for k := range x {
+			m[j].Mean += x[k] * g.w[k]
+		}
+		m[j].P05, m[j].P50, m[j].P95 = m[j].Quantile(.05), m[j].Quantile(.5), m[j].Quantile(.95)
+	}
+	return m, nil
+}
+
+// Bounds returns the weighted lower, median and upper quantiles of the behavioural
+// simulations at each timestep; sim(i) returns the simulation of sample U[i]
+func (g *GLUE) Bounds(sim func(i int) []float64, lo, hi float64) (lower, median, upper []float64, err error) {
+	if len(g.beh) == 0 {
+		return nil, nil, nil, fmt.Errorf("glue.Bounds: behavioural set not defined")
+	}
+	s := make([][]float64, len(g.beh))
+	for k, i := range g.beh {
+		s[k] = sim(i)
+		if len(s[k]) != len(s[0]) {
+			return nil, nil, nil, fmt.Errorf("glue.Bounds: simulations of unequal length")
+		}
+	}
+	n := len(s[0])
+	lower, median, upper = make([]float64, n), make([]float64, n), make([]float64, n)
+	x := make([]float64, len(s))
+	for t := range n {
+		for k := range s {
+			x[k] = s[k][t]
+		}
+		xs, c := cdf(x, g.w)
+		lower[t], median[t], upper[t] = quantile(xs, c, lo), quantile(xs, c, .5), quantile(xs, c, hi)
+	}
+	return
+}
+
+// cdf returns the sorted values and their cumulative weights
+func cdf(x, w []float64) ([]float64, []float64) {
+	ix := make([]int, len(x))
+	for i := range ix {
+		ix[i] = i
+	}
+	sort.Slice(ix, func(a, b int) bool { return x[ix[a]] < x[ix[b]] })
+	xs, c := make([]float64, len(x)), make([]float64, len(x))
+	cw := 0.
+	for k, i := range ix {
+		cw += w[i]
+		xs[k], c[k] = x[i], cw
+	}
+	return xs, c
+}
+
+// quantile returns the value at cumulative probability p, interpolating between samples
+func quantile(xs, c []float64, p float64) float64 {
+	k := sort.SearchFloat64s(c, p)
+	switch {
+	case k == 0:
+		return xs[0]
+	case k >= len(c):
+		return xs[len(xs)-1]
+	case c[k] == c[k-1]:
+		return xs[k]
+	}
+	return xs[k-1] + (xs[k]-xs[k-1])*(p-c[k-1])/(c[k]-c[k-1])
+}
@@ -0,0 +1,99 @@
+package glue
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	rr "github.com/maseology/goHydro/rainrun"
+	"github.com/maseology/goHydro/rainrun/sample"
+	"github.com/maseology/mmio"
+)
+
+// Prediction holds the uncertainty bounds of a simulated hydrograph
+type Prediction struct {
+	DT                           []time.Time
+	Obs, Lower, Median, Upper    []float64
+	Best                         []float64 // simulation of greatest likelihood
+	Nbehavioural                 int
+	LowerQuantile, UpperQuantile float64
+}
+
+// Predict simulates the behavioural samples of a registered rainrun model (as
+// returned from sample.Sample) and returns the likelihood-weighted prediction bounds
+func (g *GLUE) Predict(frc *rr.Frc, mdl string, lo, hi float64) (*Prediction, error) {
+	mi, ok := rr.Lookup(mdl)
+	if !ok {
+		return nil, fmt.Errorf("glue.Predict: unrecognized model: %s", mdl)
+	}
+	smpl, err := sample.Get(mdl)
+	if err != nil {
+		return nil, err
+	}
+	if len(g.beh) == 0 {
+		return nil, fmt.Errorf("glue.Predict: behavioural set not defined")
+	}
+
+	sims := make(map[int][]float64, len(g.beh))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	ch := make(chan int)
+	errs := make(chan error, len(g.beh))
+	for range runtime.GOMAXPROCS(0) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range ch {
+				m, err := mi.BuildFor(frc.Timestep, smpl(g.U[i], frc.Timestep)...)
+				if err != nil {
+					errs <- err
+					continue
+				}
+				s := make([]float64, len(frc.D))
+				for t, v := range frc.D {
+					_, _, r, _ := m.Update(&v)
+					s[t] = r
+				}
+				mu.Lock()
+				sims[i] = s
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, i := range g.beh {
+		ch <- i
+	}
+	close(ch)
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+
+	p := Prediction{DT: frc.DT, Obs: make([]float64, len(frc.D)), Nbehavioural: len(g.beh), LowerQuantile: lo, UpperQuantile: hi}
+	for t, v := range frc.D {
+		p.Obs[t] = v.Q
+	}
+	p.Best = sims[g.beh[0]]
+	if p.Lower, p.Median, p.Upper, err = g.Bounds(func(i int) []float64 { return sims[i] }, lo, hi); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// WriteCSV saves the uncertainty band using the date,obs,sim layout of rainrun.SumHydrograph,
+// where sim is the weighted median, followed by the lower and upper bounds and the best simulation
+func (p *Prediction) WriteCSV(fp string) {
+	n := len(p.DT)
+	idt, io, is, il, iu, ib := make([]interface{}, n), make([]interface{}, n), make([]interface{}, n), make([]interface{}, n), make([]interface{}, n), make([]interface{}, n)
+	for i, t := range p.DT {
+		idt[i] = t
+		io[i] = p.Obs[i]
+		is[i] = p.Median[i]
+		il[i] = p.Lower[i]
+		iu[i] = p.Upper[i]
+		ib[i] = p.Best[i]
+	}
+	mmio.WriteCSV(fp, fmt.Sprintf("date,obs,sim,q%02.0f,q%02.0f,best", p.LowerQuantile*100., p.UpperQuantile*100.), idt, io, is, il, iu, ib)
+}
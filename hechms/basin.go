@@ -1,7 +1,9 @@
 package hechms
 
 type basin struct {
-	trnfrm, qlag                                                    []float64
-	Pe, F, Q, qbf, ia, cn, scn, area, fimp, peak, tfnext, k, rp, tp float64 // Pe, F, Q are cumulative effective precip, infiltration and runoff
-	mid, dsid                                                       int
+	lss                                                  loss
+	rsv                                                  *reservoir // nil without
+	trnfrm, qlag                                         []float64
+	qbf, ia, cn, area, fimp, peak, tfnext, k, rp, tp, dt float64 // dt: timestep [hr]
+	mid, dsid                                            int
 }
@@ -0,0 +1,27 @@
+package hechms
+
+// deficitconstant loss: a single soil layer of capacity dmax must be filled
+// before losses occur at a constant rate. Without precipitation, the deficit
+// recovers toward dmax, allowing for continuous simulation.
+type deficitconstant struct {
+	d, dmax, fc, rec float64 // deficit and capacity [mm], constant and recovery rates [mm/hr]
+}
+
+func (l *deficitconstant) excess(p, dt float64) float64 {
+	if p <= 0. {
+		l.d = min(l.d+l.rec*dt, l.dmax)
+		return 0.
+	}
+	if l.d > 0 {
+		if p <= l.d {
+			l.d -= p
+			return 0.
+		}
+		p -= l.d
+		l.d = 0.
+	}
+	if q := p - l.fc*dt; q > 0 {
+		return q
+	}
+	return 0.
+}
@@ -0,0 +1,32 @@
+package hechms
+
+import "math"
+
+// exponential loss of HEC-1, where the loss rate decays with cumulative loss
+// (USACE, 1998, HEC-1 Flood Hydrograph Package, sec. 2.3.3):
+//
+//	rate = (AK + DLTK) P^ERAIN
+//	AK = STRKR / RTIOL^(0.1 CUML)
+//	DLTK = 0.2 DLTKR (1 - CUML/DLTKR)^2, while CUML < DLTKR
+//
+// evaluated in inches and hours.
+type exponential struct {
+	dltkr, strkr, rtiol, erain, cuml float64 // cuml: cumulative loss [mm]
+}
+
+func (l *exponential) excess(p, dt float64) float64 {
+	if p <= 0. {
+		return 0.
+	}
+	const mm2in = 1. / 25.4
+	cuml, dltkr := l.cuml*mm2in, l.dltkr*mm2in
+	ak := l.strkr / math.Pow(l.rtiol, .1*cuml)
+	dltk := 0.
+	if cuml < dltkr {
+		dltk = .2 * dltkr * math.Pow(1-cuml/dltkr, 2)
+	}
+	rate := (ak + dltk) * math.Pow(p*mm2in/dt, l.erain) // [in/hr]
+	f := min(rate*dt/mm2in, p)
+	l.cuml += f
+	return p - f
+}
@@ -0,0 +1,136 @@
+package hechms
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ImportError reports a HEC-HMS project file that cannot be imported,
+// including elements and methods not supported by hechms
+type ImportError struct {
+	File string
+	Line int // 0 when not specific to a line
+	Msg  string
+}
+
+func (e *ImportError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("hechms.Import: %s line %d: %s", e.File, e.Line, e.Msg)
+	}
+	return fmt.Sprintf("hechms.Import: %s: %s", e.File, e.Msg)
+}
+
+// hmsBlock is a "Kind: Name" ... "End:" block of a HEC-HMS text file
+type hmsBlock struct {
+	kind, name string
+	line       int
+	keys       []hmsKey
+	fp         string
+}
+
+type hmsKey struct {
+	key, val string
+	line     int
+}
+
+// readHMS reads the blocks of a HEC-HMS text file (e.g., .basin, .met)
+func readHMS(fp string) ([]*hmsBlock, error) {
+	f, err := os.Open(fp)
+	if err != nil {
+		return nil, &ImportError{fp, 0, err.Error()}
+	}
+	defer f.Close()
+
+	var blks []*hmsBlock
+	var cur *hmsBlock
+	sc := bufio.NewScanner(f)
+	for ln := 1; sc.Scan(); ln++ {
+		s := strings.TrimSpace(sc.Text())
+		if s == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(s, ":")
+		if !ok {
+			continue // free text (e.g., a continued description)
+		}
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		switch {
+		case k == "End":
+			if cur == nil {
+				return nil, &ImportError{fp, ln, "End: outside of a block"}
+			}
+			blks = append(blks, cur)
+			cur = nil
+		case cur == nil:
+			cur = &hmsBlock{kind: k, name: v, line: ln, fp: fp}
+		default:
+			cur.keys = append(cur.keys, hmsKey{k, v, ln})
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, &ImportError{fp, 0, err.Error()}
+	}
+	if cur != nil {
+		return nil, &ImportError{fp, cur.line, fmt.Sprintf("%s %s: missing End:", cur.kind, cur.name)}
+	}
+	return blks, nil
+}
+
+// hmsName normalizes a key or method name: lower case, letters and digits only
+func hmsName(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// get returns the value of the first of keys found
+func (b *hmsBlock) get(keys ...string) (hmsKey, bool) {
+	for _, k := range keys {
+		for _, kv := range b.keys {
+			if hmsName(kv.key) == hmsName(k) {
+				return kv, true
+			}
+		}
+	}
+	return hmsKey{}, false
+}
+
+func (b *hmsBlock) str(keys ...string) string {
+	kv, _ := b.get(keys...)
+	return kv.val
+}
+
+func (b *hmsBlock) errorf(line int, format string, a ...interface{}) error {
+	if line == 0 {
+		line = b.line
+	}
+	return &ImportError{b.fp, line, fmt.Sprintf("%s %s: ", b.kind, b.name) + fmt.Sprintf(format, a...)}
+}
+
+// float returns the value of the first of keys found, an error when none are
+func (b *hmsBlock) float(keys ...string) (float64, error) {
+	kv, ok := b.get(keys...)
+	if !ok {
+		return 0, b.errorf(0, "%s required", keys[0])
+	}
+	v, err := strconv.ParseFloat(kv.val, 64)
+	if err != nil {
+		return 0, b.errorf(kv.line, "%s: %v", kv.key, err)
+	}
+	return v, nil
+}
+
+// floatOr returns the value of the first of keys found, or def when none are
+func (b *hmsBlock) floatOr(def float64, keys ...string) (float64, error) {
+	if _, ok := b.get(keys...); !ok {
+		return def, nil
+	}
+	return b.float(keys...)
+}
@@ -0,0 +1,492 @@
+package hechms
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/maseology/goHydro/channel"
+	"github.com/maseology/goHydro/forcing"
+)
+
+// Project is a HEC-HMS basin model, and optionally its meteorologic model, imported into hechms
+type Project struct {
+	Domain   *Domain
+	Params   Params
+	Elements map[string]int                // subbasin ID of every HEC-HMS subbasin, reach, junction and sink
+	Weights  map[string]map[string]float64 // precipitation gage depth weights of every HEC-HMS subbasin (from the .met file)
+	local    map[int]string                // HEC-HMS subbasin name of every (non-synthesized) subbasin ID
+}
+
+// Import reads a HEC-HMS .basin file (Metric units) and, when given, a .met
+// file of gage weights, into a Domain of timestep tsmin [min] and its Params.
+//
+// Subbasins keep their loss (SCS curve number, initial and constant, deficit
+// and constant, Green and Ampt, exponential), transform (Clark, SCS, Snyder)
+// and recession baseflow. Reaches (lag, Muskingum, Muskingum-Cunge) and
+// junctions without a subbasin draining to them directly become zero-area
+// subbasins routing their inflows. As Params are global, recession constants
+// and ratios to peak are area-weighted, and initial baseflows summed.
+//
+// Elements and methods not supported by hechms are reported as an *ImportError.
+func Import(basin, met string, tsmin int) (*Project, error) {
+	blks, err := readHMS(basin)
+	if err != nil {
+		return nil, err
+	}
+
+	elems, order := make(map[string]*hmsBlock), []*hmsBlock{}
+	for _, b := range blks {
+		switch b.kind {
+		case "Basin":
+			if u := b.str("Unit System"); u != "" && hmsName(u) != "metric" {
+				return nil, b.errorf(0, "%s unit system unsupported, convert the basin model to Metric", u)
+			}
+		case "Subbasin", "Reach", "Junction", "Sink":
+			if _, ok := elems[b.name]; ok {
+				return nil, b.errorf(0, "duplicate element name")
+			}
+			elems[b.name] = b
+			order = append(order, b)
+		case "Source", "Reservoir", "Diversion":
+			return nil, b.errorf(0, "%s elements unsupported", b.kind)
+		}
+	}
+	if len(order) == 0 {
+		return nil, &ImportError{basin, 0, "no elements found"}
+	}
+	for _, b := range order {
+		if ds := b.str("Downstream"); ds != "" {
+			if _, ok := elems[ds]; !ok {
+				return nil, b.errorf(0, "downstream element %s not found", ds)
+			}
+		}
+	}
+
+	// hechms nodes: subbasins, reaches and junctions/sinks (hosted by the first subbasin draining to them)
+	prj := Project{
+		Elements: make(map[string]int, len(elems)),
+		local:    make(map[int]string),
+	}
+	var ws []SubBasinProperties
+	node := func(b *hmsBlock) *SubBasinProperties {
+		ws = append(ws, SubBasinProperties{
+			Name:      b.name,
+			Swsid:     len(ws) + 1,
+			Dsws:      -1,
+			Loss:      LossProperties{Method: InitialConstant},
+			Transform: TransformProperties{Method: UserUH, Ordinates: []float64{1.}},
+			Reach:     ReachProperties{Method: NoRouting},
+		})
+		prj.Elements[b.name] = len(ws)
+		return &ws[len(ws)-1]
+	}
+	host := make(map[string]*hmsBlock) // junction/sink: hosting subbasin
+	var bf []baseflow
+	for _, b := range order {
+		switch b.kind {
+		case "Subbasin":
+			w := node(b)
+			if err := w.importSubbasin(b); err != nil {
+				return nil, err
+			}
+			f, err := importBaseflow(b)
+			if err != nil {
+				return nil, err
+			}
+			f.area = w.Area
+			bf = append(bf, f)
+			prj.local[w.Swsid] = b.name
+			if ds := elems[b.str("Downstream")]; ds != nil && (ds.kind == "Junction" || ds.kind == "Sink") && host[ds.name] == nil {
+				host[ds.name] = b
+			}
+		case "Reach":
+			w := node(b)
+			if err := w.Reach.importReach(b); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, b := range order {
+		if (b.kind == "Junction" || b.kind == "Sink") && host[b.name] == nil {
+			node(b)
+		}
+	}
+	for j, h := range host {
+		prj.Elements[j] = prj.Elements[h.name]
+	}
+
+	// links
+	for _, b := range order {
+		ds := b.str("Downstream")
+		if b.kind == "Subbasin" {
+			if j := elems[ds]; j != nil && host[j.name] == b { // subbasin hosts its junction
+				ds = j.str("Downstream")
+			}
+		} else if b.kind == "Junction" || b.kind == "Sink" {
+			if host[b.name] != nil {
+				continue
+			}
+		}
+		if ds == "" {
+			continue // outlet
+		}
+		ws[prj.Elements[b.name]-1].Dsws = prj.Elements[ds]
+	}
+
+	if prj.Domain, err = Build(ws, nil, tsmin); err != nil {
+		return nil, err
+	}
+	prj.Params = baseflowParams(bf)
+
+	if met != "" {
+		if err := prj.importMet(met); err != nil {
+			return nil, err
+		}
+	}
+	return &prj, nil
+}
+
+func (w *SubBasinProperties) importSubbasin(b *hmsBlock) error {
+	var err error
+	if w.Area, err = b.float("Area"); err != nil {
+		return err
+	}
+	for _, k := range []string{"Canopy", "Surface"} {
+		if v := b.str(k); v != "" && hmsName(v) != "none" {
+			return b.errorf(0, "%s method %s unsupported", k, v)
+		}
+	}
+	if w.Fimp, err = b.floatOr(0, "Percent Impervious Area"); err != nil {
+		return err
+	}
+	w.Fimp /= 100.
+
+	l := &w.Loss
+	switch m := b.str("LossRate", "Loss Rate", "Loss"); hmsName(m) {
+	case "", "none":
+		*l = LossProperties{Method: InitialConstant}
+	case "scs", "scscurvenumber":
+		l.Method = SCSCurveNumber
+		if w.CN, err = b.float("Curve Number"); err != nil {
+			return err
+		}
+		if w.CN <= 0 || w.CN > 100 {
+			return b.errorf(0, "curve number %g out of range", w.CN)
+		}
+		if w.Ia, err = b.floatOr(.2*(25400./w.CN-254.), "Initial Abstraction"); err != nil { // default Ia = 0.2S
+			return err
+		}
+	case "initialconstant", "initialandconstant":
+		l.Method = InitialConstant
+		if w.Ia, err = b.float("Initial Loss"); err != nil {
+			return err
+		}
+		if l.Constant, err = b.float("Constant Loss Rate", "Constant Rate"); err != nil {
+			return err
+		}
+	case "deficitconstant", "deficitandconstant":
+		l.Method = DeficitConstant
+		if l.InitDeficit, err = b.float("Initial Deficit"); err != nil {
+			return err
+		}
+		if l.MaxDeficit, err = b.float("Maximum Deficit"); err != nil {
+			return err
+		}
+		if l.Constant, err = b.float("Constant Loss Rate", "Constant Rate"); err != nil {
+			return err
+		}
+		if l.Recovery, err = b.floatOr(0, "Recovery Rate"); err != nil {
+			return err
+		}
+	case "greenandampt", "greenampt":
+		l.Method = GreenAmpt
+		if w.Ia, err = b.floatOr(0, "Initial Loss"); err != nil {
+			return err
+		}
+		if l.ThetaI, err = b.float("Initial Content", "Initial Water Content"); err != nil {
+			return err
+		}
+		if l.ThetaS, err = b.float("Saturated Content", "Saturated Water Content", "Porosity"); err != nil {
+			return err
+		}
+		if l.Suction, err = b.float("Suction", "Wetting Front Suction"); err != nil {
+			return err
+		}
+		if l.Ksat, err = b.float("Conductivity", "Hydraulic Conductivity"); err != nil {
+			return err
+		}
+	case "exponential":
+		l.Method = Exponential
+		if l.InitialRange, err = b.float("Initial Range"); err != nil {
+			return err
+		}
+		if l.InitialCoef, err = b.float("Initial Coefficient"); err != nil {
+			return err
+		}
+		if l.CoefRatio, err = b.float("Coefficient Ratio"); err != nil {
+			return err
+		}
+		if l.PrecipExp, err = b.float("Precipitation Exponent"); err != nil {
+			return err
+		}
+	default:
+		return b.errorf(0, "loss method %s unsupported", m)
+	}
+
+	t := &w.Transform
+	switch m := b.str("Transform"); hmsName(m) {
+	case "", "none":
+	case "clark":
+		t.Method, t.Ordinates = ClarkUH, nil
+		if t.Tc, err = b.float("Time of Concentration"); err != nil {
+			return err
+		}
+		if t.R, err = b.float("Storage Coefficient"); err != nil {
+			return err
+		}
+	case "scs", "scsunithydrograph":
+		t.Method, t.Ordinates = SCSUH, nil
+		if t.Lag, err = b.float("Lag"); err != nil {
+			return err
+		}
+		t.Lag /= 60. // [min] to [hr]
+	case "snyder", "snyderunithydrograph":
+		t.Method, t.Ordinates = SnyderUH, nil
+		if t.Tp, err = b.float("Snyder Tp", "Standard Lag", "Lag"); err != nil {
+			return err
+		}
+		if t.Cp, err = b.float("Snyder Cp", "Peaking Coefficient"); err != nil {
+			return err
+		}
+	default:
+		return b.errorf(0, "transform method %s unsupported", m)
+	}
+	return nil
+}
+
+func (r *ReachProperties) importReach(b *hmsBlock) error {
+	var err error
+	switch m := b.str("Route", "Routing"); hmsName(m) {
+	case "", "none":
+	case "lag":
+		r.Method = LagRouting
+		if r.Lag, err = b.float("Lag"); err != nil {
+			return err
+		}
+		if r.Lag == 0 {
+			r.Method = NoRouting
+		}
+	case "muskingum":
+		r.Method = MuskingumRouting
+		if r.K, err = b.float("Muskingum K"); err != nil {
+			return err
+		}
+		x, err := b.float("Muskingum x")
+		if err != nil {
+			return err
+		}
+		r.X = &x
+		if kv, ok := b.get("Muskingum Steps"); ok {
+			if r.Subreaches, err = strconv.Atoi(kv.val); err != nil {
+				return b.errorf(kv.line, "%s: %v", kv.key, err)
+			}
+		}
+		if r.K == 0 {
+			r.Method = NoRouting
+		}
+	case "muskingumcunge":
+		r.Method = VariableMuskingumCunge
+		if r.Length, err = b.float("Length"); err != nil {
+			return err
+		}
+		if r.Slope, err = b.float("Slope", "Energy Slope"); err != nil {
+			return err
+		}
+		if r.Qref, err = b.float("Index Flow"); err != nil {
+			return err
+		}
+		ch := channel.Trapezoid{S: r.Slope}
+		if ch.N, err = b.float("Mannings n", "Manning's n"); err != nil {
+			return err
+		}
+		if ch.B, err = b.float("Width", "Bottom Width"); err != nil {
+			return err
+		}
+		switch s := b.str("Channel", "Shape"); hmsName(s) {
+		case "trapezoid":
+			if ch.Z1, err = b.float("Side Slope"); err != nil {
+				return err
+			}
+			ch.Z2 = ch.Z1
+		case "rectangle":
+		default:
+			return b.errorf(0, "Muskingum-Cunge channel shape %s unsupported", s)
+		}
+		r.Channel = &ch
+	default:
+		return b.errorf(0, "routing method %s unsupported", m)
+	}
+	return nil
+}
+
+// baseflow of an imported subbasin
+type baseflow struct {
+	area, q0, k, rp float64
+	recession       bool
+}
+
+func importBaseflow(b *hmsBlock) (baseflow, error) {
+	var f baseflow
+	var err error
+	switch m := b.str("Baseflow"); hmsName(m) {
+	case "", "none":
+		return f, nil
+	case "recession":
+		f.recession = true
+	default:
+		return f, b.errorf(0, "baseflow method %s unsupported", m)
+	}
+	if f.k, err = b.float("Recession Factor"); err != nil {
+		return f, err
+	}
+	if _, ok := b.get("Initial Flow/Area Ratio"); ok {
+		a, _ := b.float("Area")
+		if f.q0, err = b.float("Initial Flow/Area Ratio"); err != nil {
+			return f, err
+		}
+		f.q0 *= a
+	} else if f.q0, err = b.floatOr(0, "Initial Baseflow", "Initial Discharge"); err != nil {
+		return f, err
+	}
+	if t := b.str("Threshold Type"); t != "" && hmsName(t) != "ratiotopeak" {
+		return f, b.errorf(0, "baseflow threshold type %s unsupported", t)
+	}
+	if f.rp, err = b.float("Threshold Ratio", "Threshold Flow To Peak Ratio", "Ratio To Peak"); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+// baseflowParams: area-weighted recession constants and ratios to peak, summed initial baseflow
+func baseflowParams(bf []baseflow) Params {
+	var par Params
+	a := 0.
+	for _, f := range bf {
+		par.Q0 += f.q0
+		if f.recession {
+			par.Kbf += f.k * f.area
+			par.RatioToPeak += f.rp * f.area
+			a += f.area
+		}
+	}
+	if a > 0 {
+		par.Kbf /= a
+		par.RatioToPeak /= a
+	}
+	return par
+}
+
+// importMet reads the precipitation gage weights of a HEC-HMS .met file
+func (p *Project) importMet(fp string) error {
+	blks, err := readHMS(fp)
+	if err != nil {
+		return err
+	}
+	p.Weights = make(map[string]map[string]float64)
+	for _, b := range blks {
+		switch b.kind {
+		case "Meteorology":
+			if m := b.str("Precipitation Method", "Precip Method"); hmsName(m) != "gageweights" {
+				return b.errorf(0, "precipitation method %s unsupported, gage weights are required", m)
+			}
+		case "Subbasin":
+			if _, ok := p.Elements[b.name]; !ok {
+				return b.errorf(0, "subbasin not found in the basin model")
+			}
+			wt, gage := make(map[string]float64), ""
+			for _, kv := range b.keys {
+				switch hmsName(kv.key) {
+				case "gage":
+					gage = kv.val
+				case "depthweight":
+					if gage == "" {
+						return b.errorf(kv.line, "depth weight not preceded by a gage")
+					}
+					v, err := strconv.ParseFloat(kv.val, 64)
+					if err != nil || v < 0 {
+						return b.errorf(kv.line, "invalid depth weight %s", kv.val)
+					}
+					if v > 0 {
+						wt[gage] = v
+					}
+				}
+			}
+			s := 0.
+			for _, v := range wt {
+				s += v
+			}
+			if s <= 0 {
+				return b.errorf(0, "no gage weights given")
+			}
+			for g := range wt {
+				wt[g] /= s
+			}
+			p.Weights[b.name] = wt
+		}
+	}
+	return nil
+}
+
+// Forcing returns the gage-weighted forcings of every subbasin from those of
+// the gages, located in frc by the location IDs given by gage name; the
+// Domain is set to read them (Domain.MetXr). Zero-area subbasins (reaches and
+// junctions) receive none.
+func (p *Project) Forcing(frc *forcing.Forcing, gages map[string]int) (*forcing.Forcing, error) {
+	if p.Weights == nil {
+		return nil, fmt.Errorf("hechms.Project.Forcing: no gage weights imported")
+	}
+	nw := len(p.Domain.SBP)
+	out := forcing.Forcing{T: frc.T, IntervalSec: frc.IntervalSec, Lid: make([]int, nw), Ya: make([][]float64, nw)}
+	if frc.Ta != nil {
+		out.Ta = make([][]float64, nw)
+	}
+	if frc.Ea != nil {
+		out.Ea = make([][]float64, nw)
+	}
+	weigh := func(dst [][]float64, src [][]float64, i int, wt map[string]float64) error {
+		if src == nil {
+			return nil
+		}
+		dst[i] = make([]float64, frc.Nstep())
+		for g, w := range wt {
+			lid, ok := gages[g]
+			if !ok {
+				return fmt.Errorf("hechms.Project.Forcing: gage %s not given a location", g)
+			}
+			k, ok := frc.Index(lid)
+			if !ok {
+				return fmt.Errorf("hechms.Project.Forcing: gage %s location %d not found", g, lid)
+			}
+			for j, v := range src[k] {
+				dst[i][j] += w * v
+			}
+		}
+		return nil
+	}
+	for i, w := range p.Domain.SBP {
+		out.Lid[i] = w.Swsid
+		p.Domain.MetXr[w.Swsid] = i
+		var wt map[string]float64
+		if name, ok := p.local[w.Swsid]; ok {
+			if wt, ok = p.Weights[name]; !ok {
+				return nil, fmt.Errorf("hechms.Project.Forcing: subbasin %s has no gage weights", name)
+			}
+		}
+		for _, s := range []struct{ dst, src [][]float64 }{{out.Ya, frc.Ya}, {out.Ta, frc.Ta}, {out.Ea, frc.Ea}} {
+			if err := weigh(s.dst, s.src, i, wt); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &out, nil
+}
@@ -0,0 +1,22 @@
+package hechms
+
+// initialconstant loss: precipitation first satisfies the initial loss,
+// thereafter losses occur at a constant rate
+type initialconstant struct {
+	ia, fc float64 // remaining initial loss [mm], constant rate [mm/hr]
+}
+
+func (l *initialconstant) excess(p, dt float64) float64 {
+	if l.ia > 0 {
+		if p <= l.ia {
+			l.ia -= p
+			return 0.
+		}
+		p -= l.ia
+		l.ia = 0.
+	}
+	if q := p - l.fc*dt; q > 0 {
+		return q
+	}
+	return 0.
+}
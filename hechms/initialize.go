@@ -1,13 +1,15 @@
 package hechms
 
 import (
+	"fmt"
 	"math"
-
-	"github.com/maseology/goHydro/convolution"
 )
 
-func (m *Domain) initialize(par *Params) ([]basin, []reach, float64) {
+func (m *Domain) initialize(par *Params) ([]basin, []reach, float64, error) {
 
+	if err := par.validate(); err != nil {
+		return nil, nil, 0, err
+	}
 	if len(m.Order) < 1 {
 		m.Order = []int{0}
 		m.MetXr = map[int]int{0: 0}
@@ -21,10 +23,16 @@ func (m *Domain) initialize(par *Params) ([]basin, []reach, float64) {
 		// 	panic("hechms.Domain.Run MetID error")
 		// }
 		if _, ok := m.MetXr[w.Swsid]; !ok {
-			panic("hechms.Domain.Run Swsid (for MetID) error")
+			return nil, nil, 0, &TopologyError{w.Swsid, "no meteorological index (MetXr)"}
+		}
+		tp := .75 * par.Ct * math.Pow(w.FlowPathLen*w.CentFlowPathLen, .3) // eq 6-6 [hr]
+		if w.Transform.Method == SnyderUH && w.Transform.Tp > 0 {
+			tp = w.Transform.Tp
+		}
+		trnfrm := []float64{1.} // zero-area (e.g., reach or reservoir) elements
+		if w.Area > 0 {
+			trnfrm = w.Transform.newTransform(w.Area, tp, par.Cp, float64(m.TSmin))
 		}
-		tp := .75 * par.Ct * math.Pow(w.FlowPathLen*w.CentFlowPathLen, .3)  // eq 6-6 [hr]
-		trnfrm := convolution.Snyder2(w.Area, tp, par.Cp, float64(m.TSmin)) // []float64{1.} //
 		ds := func() int {
 			if len(m.Order) > 1 {
 				if d, ok := m.Xr[w.Dsws]; ok {
@@ -55,11 +63,11 @@ func (m *Domain) initialize(par *Params) ([]basin, []reach, float64) {
 			ia = w.Ia
 		}
 		bsn[i] = basin{
+			lss:    w.Loss.newLoss(ia, newcn),
 			ia:     ia, //par.Fia * w.Ia, // + par.Fia,
 			trnfrm: trnfrm,
 			qlag:   make([]float64, len(trnfrm)),
 			cn:     newcn,
-			scn:    25400./newcn - 254., // 25400./ w.CN / par.Fcn - 254., // mm
 			area:   w.Area,
 			fimp:   w.Fimp,
 			peak:   -1,
@@ -67,27 +75,39 @@ func (m *Domain) initialize(par *Params) ([]basin, []reach, float64) {
 			k:      math.Pow(par.Kbf, float64(m.TSmin)/60/24), // adjust baseflow exponential from per-day to per-timestep
 			rp:     par.RatioToPeak,
 			tp:     tp,
+			dt:     float64(m.TSmin) / 60,
 			dsid:   ds,
 			mid:    m.MetXr[w.Swsid],
 			// mid:    m.Mxr[w.MetID],
 		}
 
-		// simple lag
-		rtrnfrm, r0 := convolution.DiracDelta(par.Krch*w.FlowPathLen, float64(m.TSmin))
-		lag := make([]float64, len(rtrnfrm))
-		rch[i] = &simplelag{
-			trnfrm: rtrnfrm,
-			lag:    lag,
-			lag0:   r0,
-		}
-		// rch[i] = NewMuskingum(0., par.Krch, par.Q0, float64(m.TSmin)/60)
-
 		totarea += w.Area
 	}
 	mm2cms := totarea * 1000. / 60. / float64(m.TSmin) // convert mm to cms
 	for _, i := range m.Order {
 		bsn[i].qbf = par.Q0 / mm2cms // convert cms to mm
+		if s, ok := bsn[i].lss.(*sma); ok {
+			bsn[i].qbf = s.baseflow(bsn[i].dt) * (1 - bsn[i].fimp) // initial groundwater flow
+		}
+	}
+
+	// reaches and reservoirs, initially conveying upstream baseflow [mm.km2]
+	q0 := make([]float64, len(m.Order))
+	for _, i := range m.Order {
+		r, err := m.SBP[i].Reach.newReach(&m.SBP[i], par, float64(m.TSmin), q0[i])
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("hechms: subbasin %d: %v", m.SBP[i].Swsid, err)
+		}
+		rch[i] = r
+		qo := bsn[i].qbf*bsn[i].area + q0[i]
+		if r := m.SBP[i].Reservoir; r != nil {
+			bsn[i].rsv = r.newReservoir(float64(m.TSmin), qo)
+			qo = bsn[i].rsv.qt
+		}
+		if d := bsn[i].dsid; d >= 0 {
+			q0[d] += qo
+		}
 	}
 
-	return bsn, rch, totarea
+	return bsn, rch, totarea, nil
 }
@@ -0,0 +1,122 @@
+package hechms
+
+import "fmt"
+
+// loss converts precipitation to excess, called every timestep (including
+// those without precipitation, allowing losses to recover)
+type loss interface {
+	excess(p, dt float64) float64 // precipitation excess [mm] of p [mm] falling over dt [hr]
+}
+
+// LossMethod of a subbasin
+type LossMethod int
+
+const (
+	SCSCurveNumber         LossMethod = iota // SCS curve number (Ia, CN), the default
+	InitialConstant                          // initial (Ia) and constant loss
+	DeficitConstant                          // deficit and constant loss, recovering between events
+	GreenAmpt                                // Green-Ampt infiltration following an initial loss (Ia)
+	Exponential                              // HEC-1 exponential loss
+	SoilMoistureAccounting                   // continuous soil moisture accounting, also yielding evapotranspiration and baseflow
+)
+
+func (l LossMethod) String() string {
+	switch l {
+	case SCSCurveNumber:
+		return "SCS curve number"
+	case InitialConstant:
+		return "initial and constant"
+	case DeficitConstant:
+		return "deficit and constant"
+	case GreenAmpt:
+		return "Green-Ampt"
+	case Exponential:
+		return "exponential"
+	case SoilMoistureAccounting:
+		return "soil moisture accounting"
+	}
+	return fmt.Sprintf("LossMethod(%d)", int(l))
+}
+
+// LossProperties of a subbasin; only those of the chosen Method are used.
+// The initial loss of the SCS, initial-constant and Green-Ampt methods is
+// SubBasinProperties.Ia, scaled by Params.Fia.
+type LossProperties struct {
+	Method LossMethod
+
+	Constant float64 // initial-constant, deficit-constant: constant loss rate [mm/hr]
+
+	MaxDeficit, InitDeficit float64 // deficit-constant: soil storage capacity and initial deficit [mm]
+	Recovery                float64 // deficit-constant: rate the deficit recovers without precipitation [mm/hr]
+
+	Ksat, Suction  float64 // Green-Ampt: saturated hydraulic conductivity [mm/hr], wetting front suction [mm]
+	ThetaI, ThetaS float64 // Green-Ampt: initial and saturated volumetric water content [-]
+
+	InitialRange, InitialCoef float64 // exponential: DLTKR [mm] and STRKR (HEC-1, inch-hour units)
+	CoefRatio, PrecipExp      float64 // exponential: RTIOL [-] and ERAIN [-]
+
+	SMA *SMAProperties // soil moisture accounting
+}
+
+func (l LossProperties) validate(ia float64) error {
+	neg := func(names string, v ...float64) error {
+		for _, x := range v {
+			if x < 0 {
+				return fmt.Errorf("%s loss: %s must be non-negative", l.Method, names)
+			}
+		}
+		return nil
+	}
+	switch l.Method {
+	case SCSCurveNumber:
+		return neg("Ia", ia)
+	case InitialConstant:
+		return neg("Ia and constant rate", ia, l.Constant)
+	case DeficitConstant:
+		if err := neg("constant rate, deficits and recovery", l.Constant, l.MaxDeficit, l.InitDeficit, l.Recovery); err != nil {
+			return err
+		}
+		if l.InitDeficit > l.MaxDeficit {
+			return fmt.Errorf("%s loss: initial deficit (%g) exceeds the maximum (%g)", l.Method, l.InitDeficit, l.MaxDeficit)
+		}
+	case GreenAmpt:
+		if err := neg("Ia, Ksat and suction", ia, l.Ksat, l.Suction); err != nil {
+			return err
+		}
+		if l.ThetaI < 0 || l.ThetaS > 1 || l.ThetaI > l.ThetaS {
+			return fmt.Errorf("%s loss: 0 <= ThetaI <= ThetaS <= 1 required, %g and %g given", l.Method, l.ThetaI, l.ThetaS)
+		}
+	case Exponential:
+		if err := neg("initial range, coefficient and exponent", l.InitialRange, l.InitialCoef, l.PrecipExp); err != nil {
+			return err
+		}
+		if l.CoefRatio < 1 {
+			return fmt.Errorf("%s loss: coefficient ratio must be at least 1, %g given", l.Method, l.CoefRatio)
+		}
+		if l.PrecipExp > 1 {
+			return fmt.Errorf("%s loss: precipitation exponent must be within [0,1], %g given", l.Method, l.PrecipExp)
+		}
+	case SoilMoistureAccounting:
+		return l.SMA.validate()
+	default:
+		return fmt.Errorf("unknown loss method: %d", int(l.Method))
+	}
+	return nil
+}
+
+// newLoss builds the loss of a subbasin, given its initial loss [mm] and curve number
+func (l LossProperties) newLoss(ia, cn float64) loss {
+	switch l.Method {
+	case InitialConstant:
+		return &initialconstant{ia: ia, fc: l.Constant}
+	case DeficitConstant:
+		return &deficitconstant{d: l.InitDeficit, dmax: l.MaxDeficit, fc: l.Constant, rec: l.Recovery}
+	case GreenAmpt:
+		return &greenampt{ia: ia, ks: l.Ksat, sdt: l.Suction * (l.ThetaS - l.ThetaI)}
+	case Exponential:
+		return &exponential{dltkr: l.InitialRange, strkr: l.InitialCoef, rtiol: l.CoefRatio, erain: l.PrecipExp}
+	case SoilMoistureAccounting:
+		return l.SMA.newSMA()
+	}
+	return &scscn{ia: ia, scn: 25400./cn - 254.} // mm
+}
@@ -1,6 +1,7 @@
 package hechms
 
 import (
+	"fmt"
 	"math"
 )
 
@@ -9,16 +10,16 @@ type muskingum struct{ icur, ilast, olast, c0, c1, c2 float64 }
 // x weighting factor [-] ""
 // k time constant or storage coefficient [hr] "travel time"
 // x=0: linear reservour S=ko; k=dt x=.5 translation by k
-func NewMuskingum(x, k, q0, dt float64) *muskingum {
+// returns an error when the coefficients are unstable (e.g., 2kx > dt)
+func NewMuskingum(x, k, q0, dt float64) (*muskingum, error) {
 	c0 := (dt - 2*k*x) / (2*k*(1-x) + dt)
 	c1 := (dt + 2*k*x) / (2*k*(1-x) + dt)
 	c2 := (2*k*(1-x) - dt) / (2*k*(1-x) + dt)
 	if c0 < 0 {
-		panic("muskingum error: c0 < 0 ")
+		return nil, fmt.Errorf("hechms.NewMuskingum: negative coefficient c0 = %g (x = %g, k = %g, dt = %g)", c0, x, k, dt)
 	}
-	if math.Abs(c0+c1+c2-1) > 1e-5 {
-		println(c0 + c1 + c2)
-		panic("muskingum error1")
+	if math.Abs(c0+c1+c2-1) > 1e-5 || math.IsNaN(c0+c1+c2) {
+		return nil, fmt.Errorf("hechms.NewMuskingum: coefficients sum to %g (x = %g, k = %g, dt = %g)", c0+c1+c2, x, k, dt)
 	}
 	// fmt.Println(c0, c1, c2)
 	return &muskingum{
@@ -27,7 +28,7 @@ func NewMuskingum(x, k, q0, dt float64) *muskingum {
 		c2:    c2,
 		olast: q0,
 		ilast: q0,
-	}
+	}, nil
 }
 
 func (mk *muskingum) Update(i float64) (o float64) {
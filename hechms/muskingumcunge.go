@@ -1,9 +1,6 @@
 package hechms
 
-import (
-	"fmt"
-	"math"
-)
+import "math"
 
 /*
 https://uon.sdsu.edu/variable_parameter_muskingum_cunge_method_revisited.html
@@ -13,138 +10,71 @@ https://ponce.sdsu.edu/muskingum_cunge_method_explained.html
 https://www.lmnoeng.com/Channels/trapezoid.php
 */
 
-func MCvar() {
-	Qp := 1000. // cms
-	Ap := 400.  // m2
-	L := 14.4   // km
-	beta := 1.6
-	ts := 1. // timestep [hr]
-
-	Vp := Qp / Ap
-	cp := beta * Vp                    // flood wave celerity
-	dt := math.Min(ts*3600, L*1000/Vp) // timestep
-	dx := cp * dt
-
-	fmt.Println(dx, dt)
-
+// muskingumcunge routes flows [mm.km2/timestep] through a reach divided into
+// subreaches of length dx; coefficients follow from the celerity and unit
+// discharge of the section at the reference discharge (constant parameter) or
+// at the 3-point average of known flows every timestep (variable parameter;
+// Ponce and Chaganti, 1994)
+type muskingumcunge struct {
+	sec                    section
+	ilast, olast           []float64 // last inflow and outflow of each subreach
+	c0, c1, c2             float64   // constant-parameter coefficients
+	icur, dx, dt, s, tocms float64   // dx [m], dt [s], tocms converts flows to m³/s
+	variable               bool
 }
 
-func MC() {
-
-	Qp := 1000.    // cms
-	Qb := 0.       // cms
-	Ap := 400.     // m2
-	Tp := 100.     // m
-	So := 0.000868 // [-]
-	beta := 1.6    // [-]
-	L := 14.4      // km
-	dt := 1.       // [hr]
-
-	dx := L * 1000          // [m]
-	Qo := Qb + (Qp-Qb)/2    // reference discharge
-	V := Qp / Ap            // channel velocity
-	qo := Qp / Tp           // unit discharge (at reference discharge)
-	c := beta * V           // flood wave celerity
-	C := c * dt * 3600 / dx // Courant number
-	D := qo / So / c / dx
+func newMuskingumCunge(sec section, length, slope, qref, q0, tsmin float64, variable bool) *muskingumcunge {
+	mc := muskingumcunge{
+		sec:      sec,
+		dt:       tsmin * 60,
+		s:        slope,
+		tocms:    1000. / 60. / tsmin,
+		variable: variable,
+	}
 
-	_ = Qo
+	// subreach length (Ponce, 1989): dx <= (c dt + q/(S c))/2
+	w, c := sec.at(qref)
+	n := max(int(math.Ceil(length/((c*mc.dt+qref/w/slope/c)/2))), 1)
+	mc.dx = length / float64(n)
+	mc.c0, mc.c1, mc.c2 = mc.coefficients(qref)
 
-	c0 := (-1 + C + D) / (1 + C + D)
-	c1 := (1 + C - D) / (1 + C + D)
-	c2 := (1 - C + D) / (1 + C + D)
+	mc.ilast, mc.olast = make([]float64, n), make([]float64, n)
+	for j := range n {
+		mc.ilast[j], mc.olast[j] = q0, q0
+	}
+	return &mc
+}
 
-	fmt.Println(c0, c1, c2, c0+c1+c2)
+// coefficients at discharge q [m³/s]
+func (mc *muskingumcunge) coefficients(q float64) (c0, c1, c2 float64) {
+	w, c := mc.sec.at(q)
+	C := c * mc.dt / mc.dx        // Courant number
+	D := q / w / mc.s / c / mc.dx // cell Reynolds number
+	c0 = (-1 + C + D) / (1 + C + D)
+	c1 = (1 + C - D) / (1 + C + D)
+	c2 = (1 - C + D) / (1 + C + D)
+	return
+}
 
-	Qin := []float64{0, 200, 400, 600, 800, 1000, 800, 600, 400, 200, 0, 0, 0, 0}
-	Qout := make([]float64, len(Qin))
-	for i, I := range Qin {
-		if i == 0 {
-			Qout[i] = Qb
-		} else {
-			Qout[i] = c0*I + c1*Qin[i-1] + c2*Qout[i-1]
+func (mc *muskingumcunge) Update(i float64) (o float64) {
+	if i >= 0 {
+		mc.icur += i
+		return
+	}
+	o, mc.icur = mc.icur, 0
+	for j := range mc.ilast {
+		c0, c1, c2 := mc.c0, mc.c1, mc.c2
+		if mc.variable {
+			q := (o + mc.ilast[j] + mc.olast[j]) / 3 * mc.tocms
+			if q <= 0 {
+				mc.ilast[j], mc.olast[j] = o, o
+				continue
+			}
+			c0, c1, c2 = mc.coefficients(q)
 		}
+		in := o
+		o = max(c0*in+c1*mc.ilast[j]+c2*mc.olast[j], 0)
+		mc.ilast[j], mc.olast[j] = in, o
 	}
-	fmt.Println(Qout)
+	return
 }
-
-// const ncell = 200
-
-// type muskingumcunge struct {
-// 	rchs                       []muskingumcungeseg
-// 	dt, beta, s, Qo, L, So, co float64
-// }
-
-// // type muskingumcungeseg struct{ icur, ilast, olast, c0, c1, c2 float64 }
-// type muskingumcungeseg struct{ q0, q1 float64 }
-
-// // dx [m]; dt [hr]
-// func NewMuskingumCunge(Qp, Qb, So, beta, L, dt float64) *muskingumcunge {
-// 	// https://ponce.sdsu.edu/muskingum_cunge_method_with_variable_parameters.html
-
-// 	V := Qp / Ap
-
-// 	c := beta * V
-// 	qo := Q0 / Tp
-
-// 	// dx := L / ncell
-
-// 	mcs := make([]muskingumcungeseg, ncell)
-// 	for j := 0; j < ncell; j++ {
-// 		mcs[j].q0 = qo
-// 		mcs[j].q1 = qo
-// 	}
-// 	return &muskingumcunge{
-// 		rchs: mcs,
-// 		// dx:   dx,
-// 		dt:   dt,
-// 		beta: beta,
-// 		s:    So,
-// 		Qo:   Qb + (Qp-Qb)/2,
-// 		L:    L, // reach length
-// 	}
-
-// }
-
-// // Variable-parameter Muskingum-Cunge method revisited (1994)
-// // https://uon.sdsu.edu/variable_parameter_muskingum_cunge_method_revisited.html
-// func (mc *muskingumcunge) Update(i float64) (o float64) {
-
-// 	dx := mc.co * mc.dt
-// 	if dx >= (mc.co*mc.dt+mc.Qo/mc.T/mc.So/mc.co)/2 {
-
-// 	}
-
-// 	if i < 0 {
-// 		// mc := *mc
-// 		for j := 1; j < ncell; j++ {
-// 			q := (mc.rchs[j-1].q0 + mc.rchs[j-1].q1 + mc.rchs[j].q0) / 3
-// 			c := mc.beta * q / y
-
-// 			C := c * 3600 * mc.dt / mc.dx
-// 			D := q / mc.s / c / mc.dx
-
-// 			c0 := (-1 + C + D) / (1 + C + D)
-// 			c1 := (1 + C - D) / (1 + C + D)
-// 			c2 := (1 - C + D) / (1 + C + D)
-
-// 			if math.Abs(c0+c1+c2-1) > 1e-5 {
-// 				println(c0 + c1 + c2)
-// 				panic("NewMuskingumCunge error1")
-// 			}
-// 			// fmt.Println(c0, c1, c2Println(a)
-
-// 			mc.rchs[j].q0 = mc.rchs[j].q1
-// 			mc.rchs[j].q1 = q
-
-// 		}
-
-// 		// o = mc.c0*mc.icur + mc.c1*mc.ilast + mc.c2*mc.olast
-// 		mc.olast = o
-// 		mc.ilast = mc.icur
-// 		mc.icur = 0
-// 		return
-// 	}
-// 	mc.icur += i
-// 	return
-// }
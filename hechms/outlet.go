@@ -0,0 +1,85 @@
+package hechms
+
+import (
+	"fmt"
+	"math"
+)
+
+const grav = 9.80665 // [m/s²]
+
+// OutletStructure of a reservoir
+type OutletStructure int
+
+const (
+	Orifice  OutletStructure = iota // Q = Cd A √(2gh), h the head above its centreline
+	Weir                            // sharp-crested weir: Q = C L H^1.5
+	Spillway                        // broad-crested (trapezoidal) spillway: Q = C (L H^1.5 + .8 Z H^2.5)
+)
+
+func (o OutletStructure) String() string {
+	switch o {
+	case Orifice:
+		return "orifice"
+	case Weir:
+		return "weir"
+	case Spillway:
+		return "spillway"
+	}
+	return fmt.Sprintf("OutletStructure(%d)", int(o))
+}
+
+// Outlet structure of a reservoir; only the properties of its Structure are used
+type Outlet struct {
+	Structure OutletStructure
+	Elevation float64 // orifice centreline, weir or spillway crest [m]
+	Coef      float64 // discharge coefficient, when zero: orifice Cd .6 [-], weir C 1.84, spillway C 1.7 [m^.5/s]
+	Area      float64 // orifice: opening area [m²]
+	Length    float64 // weir, spillway: crest length [m]
+	SideSlope float64 // spillway: side slopes of the control section [H:V], rectangular when zero
+}
+
+func (o Outlet) validate() error {
+	if o.Coef < 0 {
+		return fmt.Errorf("reservoir %s outlet: coefficient must be non-negative, %g given", o.Structure, o.Coef)
+	}
+	switch o.Structure {
+	case Orifice:
+		if o.Area <= 0 {
+			return fmt.Errorf("reservoir %s outlet: area must be greater than zero, %g given", o.Structure, o.Area)
+		}
+	case Weir, Spillway:
+		if o.Length <= 0 || o.SideSlope < 0 {
+			return fmt.Errorf("reservoir %s outlet: length > 0 and side slope >= 0 required, %g and %g given", o.Structure, o.Length, o.SideSlope)
+		}
+	default:
+		return fmt.Errorf("unknown reservoir outlet structure: %d", int(o.Structure))
+	}
+	return nil
+}
+
+// discharge [m³/s] at pool elevation z [m]
+func (o Outlet) discharge(z float64) float64 {
+	h := z - o.Elevation
+	if h <= 0 {
+		return 0.
+	}
+	c := o.Coef
+	switch o.Structure {
+	case Orifice:
+		if c == 0 {
+			c = .6
+		}
+		return c * o.Area * math.Sqrt(2*grav*h)
+	case Weir:
+		if c == 0 {
+			c = 1.84
+		}
+		return c * o.Length * math.Pow(h, 1.5)
+	case Spillway:
+		if c == 0 {
+			c = 1.7
+		}
+		return c * (o.Length*math.Pow(h, 1.5) + .8*o.SideSlope*math.Pow(h, 2.5))
+	}
+	return 0.
+}
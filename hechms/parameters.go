@@ -1,8 +1,17 @@
 package hechms
 
+import "fmt"
+
 type Params struct {
 	Fia, Fcn             float64 // CN global multipliers
 	Cp, Ct               float64 // Snyder
 	Q0, Kbf, RatioToPeak float64 // Recession
 	Krch, Xrch           float64 // storage coeffient, muskingum weighting factor
 }
+
+func (p *Params) validate() error {
+	if p.Xrch < 0 || p.Xrch > .5 {
+		return fmt.Errorf("hechms: 0 <= Params.Xrch <= .5 required, %g given", p.Xrch)
+	}
+	return nil
+}
@@ -6,16 +6,21 @@ import (
 	"github.com/maseology/mmio"
 )
 
-func (m *Domain) Print(par Params) {
+// Print writes the subbasin parameters to BasinPrint.csv; returns the
+// errors of Run when the domain cannot be initialized
+func (m *Domain) Print(par Params) error {
 
-	bsn, _, totarea := m.initialize(&par)
+	bsn, _, totarea, err := m.initialize(&par)
+	if err != nil {
+		return err
+	}
 
 	fmt.Printf("\nTotal Area: %.1f km2\n", totarea)
 	lbsn := make([]string, len(bsn)+1)
-	lbsn[0] = "name,ia,cn,pimp,tp,cp,k,rp,lag"
+	lbsn[0] = "name,loss,transform,routing,ia,cn,pimp,tp,cp,k,rp,lag"
 	for i, b := range bsn {
 		ws := m.SBP[i]
-		lbsn[i+1] = fmt.Sprintf("%s,%f,%f,%f,%f,%f,%f,%f,%f", ws.Name, b.ia, b.cn, b.fimp*100, b.tp, par.Cp, par.Kbf, b.rp, par.Krch*ws.FlowPathLen)
+		lbsn[i+1] = fmt.Sprintf("%s,%s,%s,%s,%f,%f,%f,%f,%f,%f,%f,%f", ws.Name, ws.Loss.Method, ws.Transform.Method, ws.Reach.Method, b.ia, b.cn, b.fimp*100, b.tp, par.Cp, par.Kbf, b.rp, par.Krch*ws.FlowPathLen)
 		// fmt.Println(lbsn[i+1])
 	}
 	mmio.WriteLines("BasinPrint.csv", lbsn)
@@ -28,4 +33,17 @@ func (m *Domain) Print(par Params) {
 	// // 	// fmt.Println(lbsn[i+1])
 	// // }
 	// mmio.WriteLines("ReachPrint.csv", lrch)
+	return nil
+}
+
+// PrintResults prints the subbasin parameters as Print, then the summary of
+// the results (see RunResults), writing the hydrographs leaving each subbasin
+// to BasinHydrographs.csv
+func (m *Domain) PrintResults(par Params, res *Results) error {
+	if err := m.Print(par); err != nil {
+		return err
+	}
+	fmt.Printf("\n%v", res)
+	res.WriteHydrographs("BasinHydrographs.csv")
+	return nil
 }
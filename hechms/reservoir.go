@@ -0,0 +1,201 @@
+package hechms
+
+import "fmt"
+
+// ReservoirProperties of a reservoir or detention pond at the outlet of a
+// subbasin, routing all flow leaving the subbasin (its own runoff and that of
+// its reach) by level pool. A reservoir receiving only upstream inflows is a
+// zero-area subbasin with a reservoir (and NoRouting), placed anywhere in the
+// network.
+//
+// The pool is described by an elevation-storage table, its outflow by a
+// discharge at each elevation and/or by outlet structures discharging in
+// parallel. Above the table, storage extends linearly and tabulated discharge
+// remains that of the top elevation; overtopping is not modelled but by the
+// outlets given.
+type ReservoirProperties struct {
+	Elevation []float64 // pool elevations [m], ascending
+	Storage   []float64 // storage [1000 m³] at each elevation, non-decreasing
+	Discharge []float64 // (optional) discharge [m³/s] at each elevation, non-decreasing
+	Outlets   []Outlet  // (optional) outlet structures
+
+	Initial      ReservoirInitial // initial condition, inflow equals outflow by default
+	InitialValue float64          // initial elevation [m], storage [1000 m³] or discharge [m³/s]
+}
+
+// ReservoirInitial condition of a reservoir
+type ReservoirInitial int
+
+const (
+	InflowEqualsOutflow ReservoirInitial = iota // steady: outflow equal to the initial inflow (baseflow), the pool filled to the highest such elevation, the default
+	InitialElevation                            // pool elevation InitialValue [m]
+	InitialStorage                              // storage InitialValue [1000 m³]
+	InitialDischarge                            // outflow InitialValue [m³/s]
+)
+
+func (r ReservoirInitial) String() string {
+	switch r {
+	case InflowEqualsOutflow:
+		return "inflow equals outflow"
+	case InitialElevation:
+		return "elevation"
+	case InitialStorage:
+		return "storage"
+	case InitialDischarge:
+		return "discharge"
+	}
+	return fmt.Sprintf("ReservoirInitial(%d)", int(r))
+}
+
+func (r *ReservoirProperties) validate() error {
+	n := len(r.Elevation)
+	if n < 2 || len(r.Storage) != n {
+		return fmt.Errorf("reservoir: at least 2 elevations with storages required")
+	}
+	if r.Discharge != nil && len(r.Discharge) != n {
+		return fmt.Errorf("reservoir: a discharge is required at each of the %d elevations, %d given", n, len(r.Discharge))
+	}
+	if r.Discharge == nil && len(r.Outlets) == 0 {
+		return fmt.Errorf("reservoir: a discharge table or outlets required")
+	}
+	if r.Storage[0] < 0 || r.Storage[n-1] <= r.Storage[0] {
+		return fmt.Errorf("reservoir: storage must be non-negative and increase with elevation")
+	}
+	for i := 1; i < n; i++ {
+		if r.Elevation[i] <= r.Elevation[i-1] || r.Storage[i] < r.Storage[i-1] {
+			return fmt.Errorf("reservoir: elevations must ascend, storages must not decrease (at row %d)", i+1)
+		}
+		if r.Discharge != nil && (r.Discharge[i] < r.Discharge[i-1] || r.Discharge[0] < 0) {
+			return fmt.Errorf("reservoir: discharges must be non-negative and not decrease (at row %d)", i+1)
+		}
+	}
+	for _, o := range r.Outlets {
+		if err := o.validate(); err != nil {
+			return err
+		}
+	}
+	switch r.Initial {
+	case InflowEqualsOutflow:
+	case InitialElevation:
+		if r.InitialValue < r.Elevation[0] {
+			return fmt.Errorf("reservoir: initial elevation %g below the table (%g)", r.InitialValue, r.Elevation[0])
+		}
+	case InitialStorage, InitialDischarge:
+		if r.InitialValue < 0 {
+			return fmt.Errorf("reservoir: initial %s must be non-negative, %g given", r.Initial, r.InitialValue)
+		}
+	default:
+		return fmt.Errorf("unknown reservoir initial condition: %d", int(r.Initial))
+	}
+	return nil
+}
+
+// reservoir level-pool (modified Puls) routing, in volumes per timestep: storage [mm.km2 = 1000 m³], outflow [mm.km2/ts]
+type reservoir struct {
+	ze, se, qe []float64 // elevation [m], storage [mm.km2] and discharge [mm.km2/ts] tables
+	outlets    []Outlet
+	cf         float64 // [m³/s] to [mm.km2/ts]
+	st, qt, z  float64 // current storage, outflow and pool elevation
+	s0         float64 // initial storage
+}
+
+// newReservoir builds the reservoir at timestep tsmin [min], given its initial inflow [mm.km2/ts]
+func (r *ReservoirProperties) newReservoir(tsmin, q0 float64) *reservoir {
+	rs := reservoir{
+		ze:      r.Elevation,
+		se:      r.Storage,
+		outlets: r.Outlets,
+		cf:      tsmin * 60. / 1000.,
+	}
+	if r.Discharge != nil {
+		rs.qe = make([]float64, len(r.Discharge))
+		for i, v := range r.Discharge {
+			rs.qe[i] = v * rs.cf
+		}
+	}
+	var z0 float64
+	switch r.Initial {
+	case InitialElevation:
+		z0 = r.InitialValue
+	case InitialStorage:
+		z0 = rs.elevation(r.InitialValue)
+	case InitialDischarge:
+		z0 = rs.solve(rs.outflow, r.InitialValue*rs.cf)
+	default:
+		z0 = rs.solve(rs.outflow, q0)
+	}
+	rs.st, rs.qt = rs.storage(z0), rs.outflow(z0)
+	rs.s0, rs.z = rs.st, z0
+	return &rs
+}
+
+// interp linearly interpolates y(x) of ascending x, extrapolating the last segment beyond
+func interp(x, y []float64, v float64) float64 {
+	n := len(x)
+	if v <= x[0] {
+		return y[0]
+	}
+	i := 1
+	for i < n-1 && v > x[i] {
+		i++
+	}
+	if x[i] == x[i-1] {
+		return y[i]
+	}
+	return y[i-1] + (v-x[i-1])*(y[i]-y[i-1])/(x[i]-x[i-1])
+}
+
+func (rs *reservoir) storage(z float64) float64 { return interp(rs.ze, rs.se, z) }
+
+func (rs *reservoir) elevation(s float64) float64 { return interp(rs.se, rs.ze, s) }
+
+// outflow [mm.km2/ts] at pool elevation z
+func (rs *reservoir) outflow(z float64) float64 {
+	q := 0.
+	if rs.qe != nil {
+		q = interp(rs.ze, rs.qe, min(z, rs.ze[len(rs.ze)-1]))
+	}
+	for _, o := range rs.outlets {
+		q += o.discharge(z) * rs.cf
+	}
+	return q
+}
+
+// solve returns the highest elevation where non-decreasing f does not exceed v
+func (rs *reservoir) solve(f func(float64) float64, v float64) float64 {
+	lo, hi := rs.ze[0], rs.ze[len(rs.ze)-1]
+	if f(lo) > v {
+		return lo
+	}
+	for f(hi) <= v {
+		if hi-lo > 1e4 {
+			return hi
+		}
+		hi += hi - lo
+	}
+	for range 100 {
+		m := (lo + hi) / 2
+		if f(m) <= v {
+			lo = m
+		} else {
+			hi = m
+		}
+		if hi-lo < 1e-9 {
+			break
+		}
+	}
+	return lo
+}
+
+// route takes the inflow volume of a timestep [mm.km2], returning the volume leaving
+func (rs *reservoir) route(i float64) float64 {
+	// storage indication: S2 + O2/2 = S1 - O1/2 + I
+	si := rs.st - rs.qt/2 + i
+	z := rs.solve(func(z float64) float64 { return rs.storage(z) + rs.outflow(z)/2 }, si)
+	q := rs.outflow(z)
+	q = min(q, max(2*(si-rs.storage(z)), 0)) // the pool cannot be drawn below the table
+	o := (rs.qt + q) / 2
+	rs.st += i - o // continuity
+	rs.qt, rs.z = q, rs.elevation(rs.st)
+	return o
+}
@@ -0,0 +1,182 @@
+package hechms
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/maseology/mmio"
+)
+
+// Results of a Domain run: the series of every subbasin and its reach, and a
+// mass balance of the domain
+type Results struct {
+	Start     time.Time // start of the simulation, when the forcings are dated
+	TSmin     int       // timestep [min]
+	Outlet    []float64 // outlet hydrograph [m³/s]
+	SubBasins []SubBasinResult
+	Balance   MassBalance
+}
+
+// SubBasinResult series of a subbasin and its reach, and their summary
+type SubBasinResult struct {
+	Name  string
+	Swsid int
+	Area  float64 // [km²]
+
+	Precip, Loss, Excess, ET  []float64 // [mm/ts]; ET: actual evapotranspiration (soil moisture accounting)
+	Direct, Baseflow, Outflow []float64 // subbasin hydrographs [m³/s] (Outflow = Direct + Baseflow)
+	ReachIn, ReachOut         []float64 // reach hydrographs [m³/s]: inflows from upstream, routed outflows
+	Junction                  []float64 // flow leaving the subbasin [m³/s] (Outflow + ReachOut, routed through its reservoir)
+	Pool, Storage             []float64 // reservoir pool elevation [m] and storage [1000 m³], nil without a reservoir
+
+	VolPrecip, VolLoss, VolExcess, VolDirect, VolBaseflow float64 // cumulative volumes [mm] over the subbasin
+	VolET                                                 float64
+	Peak, TimeToPeak                                      float64 // peak Junction flow [m³/s] and its time [hr] from the start of the simulation
+	PeakPool, PeakStorage                                 float64 // reservoir peak pool elevation [m] and storage [1000 m³]
+}
+
+// MassBalance of the domain [mm]; Error is the excess unaccounted for by the
+// direct runoff and the excess remaining in transforms at the end of the run,
+// RoutingError the runoff unaccounted for by the outlet and the change in
+// reach and reservoir storage
+type MassBalance struct {
+	Precip, Loss, Excess, Direct, Baseflow, Outlet   float64
+	ET, DeepPercolation, SoilStorage                 float64 // soil moisture accounting: evapotranspiration, deep percolation, change in storage
+	TransformStorage, ReachStorage, ReservoirStorage float64 // ReservoirStorage: change over the run
+	Error, RoutingError                              float64
+}
+
+func newResults(m *Domain, start time.Time, ns int) *Results {
+	res := Results{
+		Start:     start,
+		TSmin:     m.TSmin,
+		SubBasins: make([]SubBasinResult, len(m.SBP)),
+	}
+	for i, w := range m.SBP {
+		res.SubBasins[i] = SubBasinResult{
+			Name:     w.Name,
+			Swsid:    w.Swsid,
+			Area:     w.Area,
+			Precip:   make([]float64, ns),
+			Loss:     make([]float64, ns),
+			Excess:   make([]float64, ns),
+			ET:       make([]float64, ns),
+			Direct:   make([]float64, ns),
+			Baseflow: make([]float64, ns),
+			Outflow:  make([]float64, ns),
+			ReachIn:  make([]float64, ns),
+			ReachOut: make([]float64, ns),
+			Junction: make([]float64, ns),
+		}
+		if w.Reservoir != nil {
+			res.SubBasins[i].Pool = make([]float64, ns)
+			res.SubBasins[i].Storage = make([]float64, ns)
+		}
+	}
+	return &res
+}
+
+// summarize computes volumes, peaks and the mass balance, given the outlet
+// hydrograph [m³/s], the basins at the end of the run and the domain area [km²]
+func (r *Results) summarize(outlet []float64, bsn []basin, totarea float64) {
+	r.Outlet = outlet
+	dthr := float64(r.TSmin) / 60.
+	tomm := func(cms, area float64) float64 { return cms * 60. * float64(r.TSmin) / 1000. / area } // [m³/s] to [mm/ts]
+	mb := &r.Balance
+	for i := range r.SubBasins {
+		s := &r.SubBasins[i]
+		rin, rout := 0., 0.
+		for j := range s.Precip {
+			s.VolPrecip += s.Precip[j]
+			s.VolLoss += s.Loss[j]
+			s.VolExcess += s.Excess[j]
+			s.VolET += s.ET[j]
+			if s.Area > 0 {
+				s.VolDirect += tomm(s.Direct[j], s.Area)
+				s.VolBaseflow += tomm(s.Baseflow[j], s.Area)
+			}
+			rin += tomm(s.ReachIn[j], totarea)
+			rout += tomm(s.ReachOut[j], totarea)
+			if s.Junction[j] > s.Peak {
+				s.Peak, s.TimeToPeak = s.Junction[j], float64(j+1)*dthr
+			}
+		}
+		f := s.Area / totarea
+		mb.Precip += s.VolPrecip * f
+		mb.Loss += s.VolLoss * f
+		mb.Excess += s.VolExcess * f
+		mb.ET += s.VolET * f
+		mb.Direct += s.VolDirect * f
+		mb.Baseflow += s.VolBaseflow * f
+		mb.ReachStorage += rin - rout
+		for j := range s.Pool {
+			if s.Pool[j] > s.PeakPool || j == 0 {
+				s.PeakPool, s.PeakStorage = s.Pool[j], s.Storage[j]
+			}
+		}
+		if sm, ok := bsn[i].lss.(*sma); ok {
+			mb.SoilStorage += (sm.storage() - sm.s0) * (1 - bsn[i].fimp) * f
+			mb.DeepPercolation += sm.deep * (1 - bsn[i].fimp) * f
+		}
+		if rs := bsn[i].rsv; rs != nil {
+			mb.ReservoirStorage += (rs.st - rs.s0) / totarea
+		}
+		for _, q := range bsn[i].qlag {
+			mb.TransformStorage += q * f
+		}
+	}
+	for _, q := range outlet {
+		mb.Outlet += tomm(q, totarea)
+	}
+	mb.Error = mb.Excess - mb.Direct - mb.TransformStorage
+	mb.RoutingError = mb.Direct + mb.Baseflow - mb.Outlet - mb.ReachStorage - mb.ReservoirStorage
+}
+
+// String summarizes the results of every subbasin and the domain mass balance
+func (r *Results) String() string {
+	s := fmt.Sprintf("%16s %10s %10s %10s %10s %10s %10s %12s %8s\n", "subbasin", "area", "precip", "loss", "excess", "direct", "baseflow", "peak(cms)", "tp(hr)")
+	for _, b := range r.SubBasins {
+		s += fmt.Sprintf("%16s %10.2f %10.2f %10.2f %10.2f %10.2f %10.2f %12.3f %8.2f\n", b.Name, b.Area, b.VolPrecip, b.VolLoss, b.VolExcess, b.VolDirect, b.VolBaseflow, b.Peak, b.TimeToPeak)
+	}
+	for _, b := range r.SubBasins {
+		if b.Pool != nil {
+			s += fmt.Sprintf("%16s reservoir peak pool: %.3f m, storage: %.3f (1000 m³)\n", b.Name, b.PeakPool, b.PeakStorage)
+		}
+	}
+	mb := r.Balance
+	s += fmt.Sprintf("\nmass balance [mm]\n precip: %.3f  loss: %.3f  excess: %.3f\n direct: %.3f  baseflow: %.3f  outlet: %.3f\n", mb.Precip, mb.Loss, mb.Excess, mb.Direct, mb.Baseflow, mb.Outlet)
+	if mb.ET > 0 || mb.SoilStorage != 0 {
+		s += fmt.Sprintf(" evapotranspiration: %.3f  deep percolation: %.3f  soil moisture change: %.3f\n", mb.ET, mb.DeepPercolation, mb.SoilStorage)
+	}
+	s += fmt.Sprintf(" remaining in transforms: %.3f  in reaches: %.3f  in reservoirs: %.3f\n error: %.3g", mb.TransformStorage, mb.ReachStorage, mb.ReservoirStorage, mb.Error)
+	if mb.Excess > 0 {
+		s += fmt.Sprintf(" (%.3g%% of excess)", 100*math.Abs(mb.Error)/mb.Excess)
+	}
+	s += fmt.Sprintf("  routing error: %.3g", mb.RoutingError)
+	if q := mb.Direct + mb.Baseflow; q > 0 {
+		s += fmt.Sprintf(" (%.3g%% of runoff)", 100*math.Abs(mb.RoutingError)/q)
+	}
+	return s + "\n"
+}
+
+// WriteHydrographs saves the outlet hydrograph and those leaving each subbasin [m³/s]
+func (r *Results) WriteHydrographs(fp string) {
+	col := func(v []float64) []interface{} {
+		c := make([]interface{}, len(v))
+		for i, x := range v {
+			c[i] = x
+		}
+		return c
+	}
+	hr := make([]float64, len(r.Outlet))
+	for j := range hr {
+		hr[j] = float64(j+1) * float64(r.TSmin) / 60.
+	}
+	hdr, cols := "hour,outlet", [][]interface{}{col(hr), col(r.Outlet)}
+	for _, b := range r.SubBasins {
+		hdr += "," + b.Name
+		cols = append(cols, col(b.Junction))
+	}
+	mmio.WriteCSV(fp, hdr, cols...)
+}
@@ -0,0 +1,153 @@
+package hechms
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/maseology/goHydro/channel"
+	"github.com/maseology/goHydro/convolution"
+)
+
+// RoutingMethod of the reach conveying upstream inflows through a subbasin
+type RoutingMethod int
+
+const (
+	LagRouting             RoutingMethod = iota // translation (Params.Krch), the default
+	MuskingumRouting                            // Muskingum (Params.Krch, Xrch)
+	MuskingumCungeRouting                       // constant-parameter Muskingum-Cunge
+	VariableMuskingumCunge                      // variable-parameter Muskingum-Cunge
+	NoRouting                                   // inflows pass through unrouted (e.g., at a junction)
+)
+
+func (r RoutingMethod) String() string {
+	switch r {
+	case LagRouting:
+		return "lag"
+	case MuskingumRouting:
+		return "Muskingum"
+	case MuskingumCungeRouting:
+		return "Muskingum-Cunge"
+	case VariableMuskingumCunge:
+		return "variable Muskingum-Cunge"
+	case NoRouting:
+		return "none"
+	}
+	return fmt.Sprintf("RoutingMethod(%d)", int(r))
+}
+
+// ReachProperties of the reach of a subbasin; only those of the chosen Method are used
+type ReachProperties struct {
+	Method RoutingMethod
+
+	Lag float64 // lag: lag time [min], Params.Krch × FlowPathLen when zero
+
+	K          float64  // Muskingum: travel time [hr], Params.Krch × FlowPathLen/60 when zero
+	X          *float64 // Muskingum: weighting factor [0,.5], Params.Xrch when nil
+	Subreaches int      // Muskingum: number of subreaches, increased where needed to keep coefficients non-negative
+
+	Length, Slope float64                  // Muskingum-Cunge: reach length [m] and bed slope [-], Channel.S when zero
+	Qref          float64                  // Muskingum-Cunge: reference discharge [m³/s] (e.g., Qbase + (Qpeak-Qbase)/2), sets the subreach length
+	Channel       *channel.Trapezoid       // Muskingum-Cunge geometry (Manning), or
+	Rating        *convolution.RatingCurve // Muskingum-Cunge geometry (tabulated)
+}
+
+func (r ReachProperties) validate() error {
+	switch r.Method {
+	case NoRouting:
+	case LagRouting:
+		if r.Lag < 0 {
+			return fmt.Errorf("%s routing: lag must be non-negative, %g given", r.Method, r.Lag)
+		}
+	case MuskingumRouting:
+		if r.K < 0 {
+			return fmt.Errorf("%s routing: K must be non-negative, %g given", r.Method, r.K)
+		}
+		if r.X != nil && (*r.X < 0 || *r.X > .5) {
+			return fmt.Errorf("%s routing: 0 <= X <= .5 required, %g given", r.Method, *r.X)
+		}
+	case MuskingumCungeRouting, VariableMuskingumCunge:
+		if (r.Channel == nil) == (r.Rating == nil) {
+			return fmt.Errorf("%s routing: either a channel or a rating curve is required", r.Method)
+		}
+		if r.Channel != nil && (r.Channel.N <= 0 || r.Channel.B < 0 || r.Channel.Z1 < 0 || r.Channel.Z2 < 0) {
+			return fmt.Errorf("%s routing: invalid channel %+v", r.Method, *r.Channel)
+		}
+		if r.Rating != nil && (len(r.Rating.Q) < 2 || len(r.Rating.A) != len(r.Rating.Q) || len(r.Rating.W) != len(r.Rating.Q)) {
+			return fmt.Errorf("%s routing: rating curve requires at least 2 discharges with areas and widths", r.Method)
+		}
+		if r.Length <= 0 || r.slope() <= 0 || r.Qref <= 0 {
+			return fmt.Errorf("%s routing: length, slope and reference discharge must be greater than zero, %g, %g and %g given", r.Method, r.Length, r.slope(), r.Qref)
+		}
+	default:
+		return fmt.Errorf("unknown routing method: %d", int(r.Method))
+	}
+	return nil
+}
+
+func (r ReachProperties) slope() float64 {
+	if r.Slope <= 0 && r.Channel != nil {
+		return r.Channel.S
+	}
+	return r.Slope
+}
+
+// newReach builds the reach of subbasin w at timestep tsmin [min], given its
+// initial (steady) inflow q0 [mm.km2/timestep]
+func (r ReachProperties) newReach(w *SubBasinProperties, par *Params, tsmin, q0 float64) (reach, error) {
+	switch r.Method {
+	case MuskingumRouting:
+		k, x := r.K, par.Xrch
+		if k <= 0 {
+			k = par.Krch * w.FlowPathLen / 60
+		}
+		if r.X != nil {
+			x = *r.X
+		}
+		dt := tsmin / 60
+		n := max(r.Subreaches, int(math.Ceil(2*k*x/dt)), 1) // c0 >= 0
+		c := make(cascade, n)
+		for j := range c {
+			var err error
+			if c[j], err = NewMuskingum(x, k/float64(n), q0, dt); err != nil {
+				return nil, err
+			}
+		}
+		return c, nil
+	case MuskingumCungeRouting, VariableMuskingumCunge:
+		var sec section
+		if r.Channel != nil {
+			sec = &trapezoidSection{r.Channel}
+		} else {
+			sec = &ratingSection{r.Rating}
+		}
+		return newMuskingumCunge(sec, r.Length, r.slope(), r.Qref, q0, tsmin, r.Method == VariableMuskingumCunge), nil
+	case NoRouting:
+		return &simplelag{trnfrm: []float64{1.}, lag: []float64{0.}}, nil
+	}
+
+	lg := r.Lag
+	if lg <= 0 {
+		lg = par.Krch * w.FlowPathLen
+	}
+	rtrnfrm, r0 := convolution.DiracDelta(lg, tsmin)
+	return &simplelag{
+		trnfrm: rtrnfrm,
+		lag:    make([]float64, len(rtrnfrm)),
+		lag0:   r0,
+	}, nil
+}
+
+// cascade of reaches routed in series
+type cascade []reach
+
+func (c cascade) Update(i float64) float64 {
+	if i >= 0 {
+		return c[0].Update(i)
+	}
+	o := c[0].Update(-1)
+	for _, r := range c[1:] {
+		r.Update(max(o, 0))
+		o = r.Update(-1)
+	}
+	return max(o, 0)
+}
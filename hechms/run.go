@@ -1,18 +1,56 @@
 package hechms
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/maseology/goHydro/forcing"
 	"github.com/maseology/goHydro/hyetograph"
 )
 
-func (m *Domain) Run(frc *forcing.Forcing, jtb, jte, offset int, par Params) ([]float64, []float64) {
+// Run the domain, returning the outlet discharge and basin-averaged precipitation;
+// returns a *TopologyError when a subbasin is not indexed to its forcing (MetXr),
+// or an error when soil moisture accounting is given no potential evaporation
+func (m *Domain) Run(frc *forcing.Forcing, jtb, jte, offset int, par Params) ([]float64, []float64, error) {
+
+	bsn, rch, totarea, err := m.initialize(&par)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := m.checkForcing(frc, bsn); err != nil {
+		return nil, nil, err
+	}
+	sim, pre, _ := m.run(frc, bsn, rch, totarea, jtb, jte, offset, false)
+	return sim, pre, nil
+
+}
 
-	bsn, rch, totarea := m.initialize(&par)
-	return m.run(frc, bsn, rch, totarea, jtb, jte, offset)
+// RunResults runs the domain as Run, returning the series of every subbasin
+// and reach, their volumes and peaks, and a mass balance
+func (m *Domain) RunResults(frc *forcing.Forcing, jtb, jte, offset int, par Params) (*Results, error) {
+	bsn, rch, totarea, err := m.initialize(&par)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkForcing(frc, bsn); err != nil {
+		return nil, err
+	}
+	sim, _, res := m.run(frc, bsn, rch, totarea, jtb, jte, offset, true)
+	res.summarize(sim, bsn, totarea)
+	return res, nil
+}
 
+// checkForcing ensures potential evaporation is given to the subbasins of soil moisture accounting loss
+func (m *Domain) checkForcing(frc *forcing.Forcing, bsn []basin) error {
+	for _, i := range m.Order {
+		if _, ok := bsn[i].lss.(*sma); ok && (frc.Ea == nil || bsn[i].mid >= len(frc.Ea)) {
+			return fmt.Errorf("hechms: subbasin %d: %s loss requires potential evaporation (forcing.Ea)", m.SBP[i].Swsid, SoilMoistureAccounting)
+		}
+	}
+	return nil
 }
 
-func (m *Domain) run(frc *forcing.Forcing, bsn []basin, rch []reach, totarea float64, jtb, jte, offset int) ([]float64, []float64) {
+func (m *Domain) run(frc *forcing.Forcing, bsn []basin, rch []reach, totarea float64, jtb, jte, offset int, record bool) ([]float64, []float64, *Results) {
 
 	mm2cms := totarea * 1000. / 60. / float64(m.TSmin) // convert mm to cms
 	timestep := m.TSmin * 60
@@ -21,6 +59,15 @@ func (m *Domain) run(frc *forcing.Forcing, bsn []basin, rch []reach, totarea flo
 	// fss := float64(substeps)
 	yf := hyetograph.Unit(substeps) //hyetograph.SCSII(substeps, 6) //
 	sim, pre := make([]float64, ns), make([]float64, ns)
+	var res *Results
+	if record {
+		var start time.Time
+		if jtb+offset < len(frc.T) {
+			start = frc.T[jtb+offset]
+		}
+		res = newResults(m, start, ns)
+	}
+	tocms := 1000. / 60. / float64(m.TSmin) // convert mm.km2 to cms
 	pcum, qcum := 0., 0.
 	for j := jtb; j <= jte; j++ {
 		for k := 0; k < substeps; k++ {
@@ -28,9 +75,16 @@ func (m *Domain) run(frc *forcing.Forcing, bsn []basin, rch []reach, totarea flo
 			qall, psum := 0., 0.
 			for _, i := range m.Order {
 				// p, q := frc.Ya[bsn[i].mid][j]/fss, 0.
-				p, q := yf[k]*frc.Ya[bsn[i].mid][j+offset], 0.
-				if p > 0. {
-					q = bsn[i].scscn(p)*(1-bsn[i].fimp) + p*bsn[i].fimp // Loss
+				p := yf[k] * frc.Ya[bsn[i].mid][j+offset]
+				sm, cont := bsn[i].lss.(*sma)
+				var q, gw, et float64
+				if cont { // continuous
+					q, gw, et = sm.step(p, frc.Ea[bsn[i].mid][j+offset]/float64(substeps), bsn[i].dt)
+				} else {
+					q = bsn[i].lss.excess(p, bsn[i].dt)
+				}
+				q = q*(1-bsn[i].fimp) + p*bsn[i].fimp // Loss
+				if q > 0. {
 					for v, u := range bsn[i].trnfrm {
 						bsn[i].qlag[v] += q * u // direct flow to transform
 					}
@@ -42,7 +96,9 @@ func (m *Domain) run(frc *forcing.Forcing, bsn []basin, rch []reach, totarea flo
 				}
 
 				tf := df + bsn[i].qbf // "total flow" [mm]
-				if df == 0. {
+				if cont {             // baseflow from groundwater
+					tf = df + gw*(1-bsn[i].fimp)
+				} else if df == 0. {
 					bsn[i].peak = -1.   // reset storm
 					bsn[i].tfnext = -1. // disable special case
 					bsn[i].qbf *= bsn[i].k
@@ -67,12 +123,33 @@ func (m *Domain) run(frc *forcing.Forcing, bsn []basin, rch []reach, totarea flo
 				psum += p * bsn[i].area
 
 				// reach routing
-				tf += rch[i].Update(-1)
+				ro := rch[i].Update(-1)
+				if res != nil {
+					r := &res.SubBasins[i]
+					r.Precip[jj], r.Loss[jj], r.Excess[jj], r.ET[jj] = p, p-q, q, et*(1-bsn[i].fimp)
+					r.Direct[jj], r.Outflow[jj] = df*bsn[i].area*tocms, tf*tocms
+					r.Baseflow[jj] = r.Outflow[jj] - r.Direct[jj]
+					r.ReachOut[jj] = ro * tocms
+				}
+				tf += ro
+				if rs := bsn[i].rsv; rs != nil {
+					tf = rs.route(tf)
+					if res != nil {
+						r := &res.SubBasins[i]
+						r.Pool[jj], r.Storage[jj] = rs.z, rs.st
+					}
+				}
+				if res != nil {
+					res.SubBasins[i].Junction[jj] = tf * tocms
+				}
 				di := bsn[i].dsid
 				if di < 0 { // farfield
 					sim[jj] += tf // [mm.km2] (assumes models with only 1 output)
 				} else {
 					rch[di].Update(tf)
+					if res != nil {
+						res.SubBasins[di].ReachIn[jj] += tf * tocms
+					}
 				}
 			}
 			// sim[jj] = qall / totarea
@@ -94,5 +171,5 @@ func (m *Domain) run(frc *forcing.Forcing, bsn []basin, rch []reach, totarea flo
 	for j := range sim {
 		sim[j] *= mm2cms
 	}
-	return sim, pre
+	return sim, pre, res
 }
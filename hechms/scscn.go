@@ -1,6 +1,11 @@
 package hechms
 
-func (s *basin) scscn(p float64) float64 {
+// scscn SCS curve number loss
+type scscn struct {
+	Pe, Q, ia, scn float64 // Pe, Q are cumulative precipitation and runoff [mm]
+}
+
+func (s *scscn) excess(p, _ float64) float64 {
 	s.Pe += p
 	if s.Pe > s.ia {
 		peia := s.Pe - s.ia
@@ -0,0 +1,34 @@
+package hechms
+
+import (
+	"sort"
+
+	"github.com/maseology/goHydro/channel"
+	"github.com/maseology/goHydro/convolution"
+)
+
+// section of a reach, returning the top width [m] and flood wave celerity [m/s] at discharge q [m³/s]
+type section interface {
+	at(q float64) (w, c float64)
+}
+
+type trapezoidSection struct{ *channel.Trapezoid }
+
+func (s *trapezoidSection) at(q float64) (w, c float64) {
+	const dy = .001 // [m]
+	y, a := s.DepthArea(q)
+	w, _, _, _ = s.Section(y)
+	_, _, a2, _ := s.Section(y + dy)
+	c = (s.Discharge(y+dy) - s.Discharge(y)) / (a2 - a) // dQ/dA
+	return
+}
+
+type ratingSection struct{ *convolution.RatingCurve }
+
+func (s *ratingSection) at(q float64) (w, c float64) {
+	j := min(max(sort.SearchFloat64s(s.Q, q), 1), len(s.Q)-1)
+	f := (q - s.Q[j-1]) / (s.Q[j] - s.Q[j-1])
+	w = s.W[j-1] + f*(s.W[j]-s.W[j-1])
+	c = (s.Q[j] - s.Q[j-1]) / (s.A[j] - s.A[j-1]) // dQ/dA
+	return
+}
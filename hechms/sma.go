@@ -0,0 +1,148 @@
+package hechms
+
+import "fmt"
+
+// SMAProperties of the soil moisture accounting (continuous) loss: canopy
+// interception, surface depression, soil profile (of which the tension zone
+// is lost only to evapotranspiration) and two groundwater layers whose
+// lateral flows are the baseflow of the subbasin. Following HEC-HMS, initial
+// storages are given in percent of their capacity.
+type SMAProperties struct {
+	CanopyMax, SurfaceMax float64 // canopy interception and surface depression storage [mm]
+	SoilMax, TensionMax   float64 // soil profile and tension zone storage [mm]
+	MaxInfiltration       float64 // infiltration rate of a dry soil profile [mm/hr]
+	SoilPercolation       float64 // maximum percolation rate from the soil profile [mm/hr]
+
+	GW1Max, GW1Percolation, GW1Coef float64 // groundwater layer 1 storage [mm], maximum percolation rate [mm/hr] and storage coefficient [hr]
+	GW2Max, GW2Percolation, GW2Coef float64 // groundwater layer 2 storage [mm], maximum (deep) percolation rate [mm/hr] and storage coefficient [hr]
+
+	InitCanopy, InitSurface, InitSoil, InitGW1, InitGW2 float64 // initial storages [%]
+}
+
+func (s *SMAProperties) validate() error {
+	if s == nil {
+		return fmt.Errorf("%s loss: properties required", SoilMoistureAccounting)
+	}
+	for _, v := range []float64{s.CanopyMax, s.SurfaceMax, s.MaxInfiltration, s.SoilPercolation, s.GW1Percolation, s.GW2Percolation} {
+		if v < 0 {
+			return fmt.Errorf("%s loss: storages and rates must be non-negative", SoilMoistureAccounting)
+		}
+	}
+	if s.SoilMax <= 0 || s.GW1Max <= 0 || s.GW2Max <= 0 || s.GW1Coef <= 0 || s.GW2Coef <= 0 {
+		return fmt.Errorf("%s loss: soil and groundwater storages and coefficients must be greater than zero", SoilMoistureAccounting)
+	}
+	if s.TensionMax < 0 || s.TensionMax > s.SoilMax {
+		return fmt.Errorf("%s loss: 0 <= tension zone <= soil storage required, %g and %g given", SoilMoistureAccounting, s.TensionMax, s.SoilMax)
+	}
+	for _, v := range []float64{s.InitCanopy, s.InitSurface, s.InitSoil, s.InitGW1, s.InitGW2} {
+		if v < 0 || v > 100 {
+			return fmt.Errorf("%s loss: initial storages must be within [0,100]%%, %g given", SoilMoistureAccounting, v)
+		}
+	}
+	return nil
+}
+
+// sma soil moisture accounting loss; storages [mm]
+type sma struct {
+	cn, sf, sl, g1, g2 float64 // canopy, surface, soil, groundwater storages
+	cnx, sfx, slx, tzx float64 // canopy, surface, soil and tension zone capacities
+	g1x, g2x           float64 // groundwater capacities
+	imax, ps, p1, p2   float64 // infiltration, soil, groundwater 1 and 2 percolation rates [mm/hr]
+	k1, k2             float64 // groundwater storage coefficients [hr]
+	deep, s0           float64 // cumulative deep percolation, initial total storage [mm]
+}
+
+func (s *SMAProperties) newSMA() *sma {
+	sm := sma{
+		cn:   s.InitCanopy / 100 * s.CanopyMax,
+		sf:   s.InitSurface / 100 * s.SurfaceMax,
+		sl:   s.InitSoil / 100 * s.SoilMax,
+		g1:   s.InitGW1 / 100 * s.GW1Max,
+		g2:   s.InitGW2 / 100 * s.GW2Max,
+		cnx:  s.CanopyMax,
+		sfx:  s.SurfaceMax,
+		slx:  s.SoilMax,
+		tzx:  s.TensionMax,
+		g1x:  s.GW1Max,
+		g2x:  s.GW2Max,
+		imax: s.MaxInfiltration,
+		ps:   s.SoilPercolation,
+		p1:   s.GW1Percolation,
+		p2:   s.GW2Percolation,
+		k1:   s.GW1Coef,
+		k2:   s.GW2Coef,
+	}
+	sm.s0 = sm.storage()
+	return &sm
+}
+
+// excess without evapotranspiration, see step
+func (s *sma) excess(p, dt float64) float64 {
+	q, _, _ := s.step(p, 0., dt)
+	return q
+}
+
+// step accounts for precipitation p [mm] and potential evapotranspiration pet
+// [mm] over dt [hr], returning the surface excess, groundwater (lateral) flow
+// and actual evapotranspiration [mm]
+func (s *sma) step(p, pet, dt float64) (q, gw, et float64) {
+	evap := func(st *float64, rate float64) {
+		e := min(pet, *st, rate)
+		*st -= e
+		pet -= e
+		et += e
+	}
+
+	// canopy
+	s.cn += p
+	thru := max(s.cn-s.cnx, 0.)
+	s.cn -= thru
+	evap(&s.cn, pet)
+
+	// infiltration, then surface depression storage
+	avail := s.sf + thru
+	inf := min(avail, s.imax*(1-s.sl/s.slx)*dt, s.slx-s.sl)
+	s.sl += inf
+	s.sf = avail - inf
+	q = max(s.sf-s.sfx, 0.)
+	s.sf -= q
+	evap(&s.sf, pet)
+
+	// soil: the upper zone evaporates at the potential rate, the tension zone at a rate reduced by its storage
+	up := max(s.sl-s.tzx, 0.)
+	evap(&up, pet)
+	s.sl = up + min(s.sl, s.tzx)
+	if s.tzx > 0 {
+		tz := min(s.sl, s.tzx)
+		evap(&s.sl, pet*tz/s.tzx)
+	}
+
+	// percolation from the upper zone, lateral flow and percolation of the groundwater layers
+	perc := min(s.ps*s.sl/s.slx*(1-s.g1/s.g1x)*dt, max(s.sl-s.tzx, 0.), s.g1x-s.g1)
+	s.sl -= perc
+	s.g1 += perc
+
+	q1 := s.g1 * min(dt/s.k1, 1.)
+	s.g1 -= q1
+	p12 := min(s.p1*s.g1/s.g1x*(1-s.g2/s.g2x)*dt, s.g1, s.g2x-s.g2)
+	s.g1 -= p12
+	s.g2 += p12
+
+	q2 := s.g2 * min(dt/s.k2, 1.)
+	s.g2 -= q2
+	dp := min(s.p2*s.g2/s.g2x*dt, s.g2)
+	s.g2 -= dp
+	s.deep += dp
+
+	return q, q1 + q2, et
+}
+
+// baseflow returns the groundwater flow [mm] over dt [hr] of the current storages
+func (s *sma) baseflow(dt float64) float64 {
+	return s.g1*min(dt/s.k1, 1.) + s.g2*min(dt/s.k2, 1.)
+}
+
+// storage returns the total of all storages [mm]
+func (s *sma) storage() float64 {
+	return s.cn + s.sf + s.sl + s.g1 + s.g2
+}
@@ -1,5 +1,7 @@
 package hechms
 
+import "fmt"
+
 type Domain struct {
 	SBP       []SubBasinProperties
 	Xr, MetXr map[int]int
@@ -15,9 +17,33 @@ type SubBasinProperties struct {
 	BasinSlope, BasinRelief, BasinRelRatio,
 	DrainDensity, Elongation, Area float64
 	MetID, Swsid, Dsws int
+	Loss               LossProperties       // loss method, SCS curve number by default
+	Transform          TransformProperties  // transform method, Snyder unit hydrograph by default
+	Reach              ReachProperties      // routing method of upstream inflows, lag by default
+	Reservoir          *ReservoirProperties // (optional) reservoir routing all flow leaving the subbasin
+}
+
+// TopologyError reports a subbasin network that cannot be ordered from headwaters
+// to outlet, or a subbasin not indexed to its meteorological forcing
+type TopologyError struct {
+	Swsid int // offending subbasin ID
+	Msg   string
 }
 
-func Build(ws []SubBasinProperties, metxr map[int]int, tsmin int) *Domain {
+func (e *TopologyError) Error() string {
+	return fmt.Sprintf("hechms: subbasin %d: %s", e.Swsid, e.Msg)
+}
+
+// Build orders the subbasins of a domain from headwaters to outlet; returns a
+// *TopologyError when subbasin IDs are duplicated or the network is cyclic,
+// or an error when the loss, transform or reach properties of a subbasin are invalid
+func Build(ws []SubBasinProperties, metxr map[int]int, tsmin int) (*Domain, error) {
+	if len(ws) == 0 {
+		return nil, fmt.Errorf("hechms.Build: no subbasins given")
+	}
+	if tsmin <= 0 {
+		return nil, fmt.Errorf("hechms.Build: timestep must be greater than zero, %d given", tsmin)
+	}
 	Usws, xr := make(map[int][]int), make(map[int]int, len(ws))
 
 	if func() bool {
@@ -29,6 +55,9 @@ func Build(ws []SubBasinProperties, metxr map[int]int, tsmin int) *Domain {
 		return false
 	}() {
 		for i, s := range ws {
+			if _, ok := xr[s.Swsid]; ok {
+				return nil, &TopologyError{s.Swsid, "duplicate subbasin ID"}
+			}
 			xr[s.Swsid] = i
 			Usws[s.Swsid] = []int{}
 		}
@@ -42,7 +71,24 @@ func Build(ws []SubBasinProperties, metxr map[int]int, tsmin int) *Domain {
 
 	tarea := 0.
 	for _, s := range ws {
+		if err := s.Loss.validate(s.Ia); err != nil {
+			return nil, fmt.Errorf("hechms.Build: subbasin %d: %v", s.Swsid, err)
+		}
+		if err := s.Transform.validate(); err != nil {
+			return nil, fmt.Errorf("hechms.Build: subbasin %d: %v", s.Swsid, err)
+		}
+		if err := s.Reach.validate(); err != nil {
+			return nil, fmt.Errorf("hechms.Build: subbasin %d: %v", s.Swsid, err)
+		}
+		if s.Reservoir != nil {
+			if err := s.Reservoir.validate(); err != nil {
+				return nil, fmt.Errorf("hechms.Build: subbasin %d: %v", s.Swsid, err)
+			}
+		}
 		tarea += s.Area
+		if s.Dsws == s.Swsid {
+			return nil, &TopologyError{s.Swsid, "drains to itself"}
+		}
 		if _, ok := xr[s.Dsws]; ok {
 			Usws[s.Dsws] = append(Usws[s.Dsws], s.Swsid)
 		}
@@ -51,7 +97,7 @@ func Build(ws []SubBasinProperties, metxr map[int]int, tsmin int) *Domain {
 	for _, s := range ws {
 		if len(Usws[s.Swsid]) == 0 {
 			if _, ok := xr[s.Swsid]; !ok {
-				panic("hechms.Build err1")
+				return nil, &TopologyError{s.Swsid, "headwater subbasin not indexed"}
 			}
 			q = append(q, xr[s.Swsid])
 		}
@@ -78,10 +124,18 @@ func Build(ws []SubBasinProperties, metxr map[int]int, tsmin int) *Domain {
 		}
 	}
 
+	if len(ord) != len(ws) {
+		for _, s := range ws {
+			if _, ok := eval[s.Swsid]; !ok {
+				return nil, &TopologyError{s.Swsid, "not reached from the headwaters, the network is cyclic"}
+			}
+		}
+	}
+
 	if metxr == nil {
 		metxr = make(map[int]int, len(ws))
-		for i := range ws {
-			metxr[i] = 0
+		for _, s := range ws {
+			metxr[s.Swsid] = 0
 		}
 	}
 
@@ -92,5 +146,5 @@ func Build(ws []SubBasinProperties, metxr map[int]int, tsmin int) *Domain {
 		Order: ord,
 		Area:  tarea,
 		TSmin: tsmin,
-	}
+	}, nil
 }
@@ -0,0 +1,145 @@
+package hechms
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/maseology/goHydro/convolution"
+	"github.com/maseology/goHydro/tem"
+)
+
+// TransformMethod converting a subbasin's precipitation excess to direct runoff
+type TransformMethod int
+
+const (
+	SnyderUH TransformMethod = iota // Snyder unit hydrograph (Params.Cp, Ct), the default
+	ClarkUH                         // Clark unit hydrograph
+	SCSUH                           // SCS dimensionless unit hydrograph
+	UserUH                          // user-specified unit hydrograph
+	ModClark                        // Clark with distributed travel times from a topologic elevation model (see TransformProperties)
+)
+
+func (t TransformMethod) String() string {
+	switch t {
+	case SnyderUH:
+		return "Snyder"
+	case ClarkUH:
+		return "Clark"
+	case SCSUH:
+		return "SCS"
+	case UserUH:
+		return "user-specified"
+	case ModClark:
+		return "ModClark"
+	}
+	return fmt.Sprintf("TransformMethod(%d)", int(t))
+}
+
+// TransformProperties of a subbasin; only those of the chosen Method are used
+type TransformProperties struct {
+	Method TransformMethod
+
+	Tp, Cp float64 // Snyder: lag [hr] and peaking coefficient, from Params.Ct (and flow path lengths) and Params.Cp when zero
+
+	Tc, R float64 // Clark, ModClark: time of concentration and storage coefficient [hr]
+
+	Lag float64 // SCS: basin lag [hr]
+
+	Ordinates []float64 // user-specified: unit hydrograph ordinates at the model timestep, normalized when built
+
+	// ModClark: cells of the subbasin, each of equal area, and its outlet cell (<0 when the TEM is the
+	// subbasin). Cell travel times are lumped into a single time-area curve routed through the Clark
+	// reservoir: precipitation excess is computed per subbasin, hence uniform over its cells, for which
+	// the curve is equivalent to routing each cell separately (gridded excess is not supported).
+	TEM    *tem.TEM
+	Outlet int
+}
+
+func (t TransformProperties) validate() error {
+	switch t.Method {
+	case SnyderUH:
+		if t.Tp < 0 || t.Cp < 0 || t.Cp > 1 {
+			return fmt.Errorf("%s transform: Tp >= 0 and 0 <= Cp <= 1 required, %g and %g given", t.Method, t.Tp, t.Cp)
+		}
+	case ClarkUH, ModClark:
+		if t.Tc <= 0 || t.R < 0 {
+			return fmt.Errorf("%s transform: Tc > 0 and R >= 0 required, %g and %g given", t.Method, t.Tc, t.R)
+		}
+		if t.Method == ModClark {
+			if t.TEM == nil || t.TEM.NumCells() == 0 {
+				return fmt.Errorf("%s transform: no TEM given", t.Method)
+			}
+			if _, ok := t.TEM.TEC[t.Outlet]; t.Outlet >= 0 && !ok {
+				return fmt.Errorf("%s transform: outlet cell %d not found in TEM", t.Method, t.Outlet)
+			}
+		}
+	case SCSUH:
+		if t.Lag <= 0 {
+			return fmt.Errorf("%s transform: lag must be greater than zero, %g given", t.Method, t.Lag)
+		}
+	case UserUH:
+		s := 0.
+		for _, v := range t.Ordinates {
+			if v < 0 {
+				return fmt.Errorf("%s transform: negative ordinate", t.Method)
+			}
+			s += v
+		}
+		if s <= 0 {
+			return fmt.Errorf("%s transform: no ordinates given", t.Method)
+		}
+	default:
+		return fmt.Errorf("unknown transform method: %d", int(t.Method))
+	}
+	return nil
+}
+
+// newTransform returns the unit hydrograph ordinates of a subbasin at
+// timestep tsmin [min]; tp [hr] and cp are the Snyder lag and peaking coefficient
+func (t TransformProperties) newTransform(area, tp, cp, tsmin float64) []float64 {
+	switch t.Method {
+	case ClarkUH:
+		return convolution.Clark(t.Tc, t.R, tsmin)
+	case SCSUH:
+		return convolution.SCS(t.Lag, tsmin)
+	case UserUH:
+		s := 0.
+		for _, v := range t.Ordinates {
+			s += v
+		}
+		o := make([]float64, len(t.Ordinates))
+		for i, v := range t.Ordinates {
+			o[i] = v / s
+		}
+		return o
+	case ModClark:
+		return convolution.ClarkTimeArea(t.timeArea(tsmin/60), t.R, tsmin)
+	}
+	if t.Cp > 0 {
+		cp = t.Cp
+	}
+	return convolution.Snyder2(area, tp, cp, tsmin)
+}
+
+// timeArea returns the fraction of subbasin cells contributing per timestep dt
+// [hr], the travel time of each cell being Tc scaled by its flow path length
+// (in cells) relative to the longest in the subbasin
+func (t TransformProperties) timeArea(dt float64) []float64 {
+	sub, _ := t.TEM.SubSet(t.Outlet)
+	ct := sub.ConcentrationTime()
+	cmx := 0
+	for _, c := range ct {
+		cmx = max(cmx, c)
+	}
+	n := max(int(math.Ceil(t.Tc/dt)), 1)
+	ta, f := make([]float64, n), 1./float64(len(ct))
+	if cmx == 0 { // single cell, or all cells at the outlet
+		ta[0] = 1.
+		return ta
+	}
+	for _, c := range ct {
+		i := min(int(t.Tc*float64(c)/float64(cmx)/dt-1e-9), n-1)
+		ta[max(i, 0)] += f
+	}
+	return ta
+}
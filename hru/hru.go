@@ -1,7 +1,8 @@
 package hru
 
 import (
-	"log"
+	"fmt"
+	"math"
 )
 
 // // bit-wise status flag
@@ -43,10 +44,22 @@ func (h *HRU) Storage() float64 {
 // 	return h.Perc, h.Fimp, h.Sma.Cap, h.Sdet.Cap
 // }
 
-// Initialize HRU
-func (h *HRU) Initialize(rzsto, srfsto, fimp, ksat, sma0, srf0 float64) {
-	if rzsto < 0. || srfsto < 0. || fimp < 0. || fimp > 1. || ksat < 0. || sma0 < 0. || srf0 < 0. {
-		log.Fatalf("HRU Initialize parameter error: rzsto=%.5f srfsto=%.5f fimp=%.5f ksat=%.5f\n", rzsto, srfsto, fimp, ksat)
+// ParameterError reports an HRU parameter out of range
+type ParameterError struct {
+	Name  string
+	Value float64
+}
+
+func (e *ParameterError) Error() string {
+	return fmt.Sprintf("hru: parameter %s out of range: %.5f", e.Name, e.Value)
+}
+
+// Initialize HRU; returns a *ParameterError when a parameter is out of range
+func (h *HRU) Initialize(rzsto, srfsto, fimp, ksat, sma0, srf0 float64) error {
+	for _, p := range []ParameterError{{"rzsto", rzsto}, {"srfsto", srfsto}, {"fimp", fimp}, {"ksat", ksat}, {"sma0", sma0}, {"srf0", srf0}} {
+		if p.Value < 0. || math.IsNaN(p.Value) || (p.Name == "fimp" && p.Value > 1.) {
+			return &p
+		}
 	}
 	// if ksat > rzsto {
 	// 	fmt.Printf("HRU Initialize parameter warning: ksat > rzsto; percolation will never exceed drainable storage")
@@ -59,7 +72,7 @@ func (h *HRU) Initialize(rzsto, srfsto, fimp, ksat, sma0, srf0 float64) {
 	// h.Fprv = 1. - fimp  // fraction pervious
 	h.Perc = ksat // gravity-driven percolation rate m/ts (unit gradient)
 	// h.Perc =  h.Fprv * ksat // gravity-driven percolation rate m/ts
-
+	return nil
 }
 
 // Reset state
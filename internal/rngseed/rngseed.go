@@ -0,0 +1,26 @@
+// Package rngseed seeds the random number generators of stochastic routines,
+// such that any run can be repeated given the seed it used.
+package rngseed
+
+import (
+	"math/rand"
+	"time"
+
+	mrg63k3a "github.com/maseology/goRNG/MRG63k3a"
+)
+
+// Clock returns seed, or one taken from the clock when zero
+func Clock(seed int64) int64 {
+	if seed == 0 {
+		return time.Now().UnixNano()
+	}
+	return seed
+}
+
+// New returns an MRG63k3a generator seeded by seed, or by the clock when zero, along with the seed used
+func New(seed int64) (*rand.Rand, int64) {
+	seed = Clock(seed)
+	rng := rand.New(mrg63k3a.New())
+	rng.Seed(seed)
+	return rng, seed
+}
@@ -48,69 +48,78 @@ func NewSlice(nam string, nds [][]float64, els [][]int, prnt bool) *Slice {
 	return &sl
 }
 
-// ReadAlgomesh imports a Algomesh grids in .ah2 or .ah3
+// FormatError reports a mesh file that could not be parsed
+type FormatError struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e *FormatError) Error() string {
+	return fmt.Sprintf("mesh: %s line %d: %v", e.File, e.Line, e.Err)
+}
+
+func (e *FormatError) Unwrap() error { return e.Err }
+
+// ReadAlgomesh imports a Algomesh grids in .ah2 or .ah3; returns a *FormatError when the file is malformed
 func ReadAlgomesh(fp string, prnt bool) (*Slice, error) {
 	file, err := os.Open(fp)
 	if err != nil {
-		return nil, fmt.Errorf("ReadGDEF: %v", err)
+		return nil, fmt.Errorf("ReadAlgomesh: %v", err)
 	}
 	defer file.Close()
 
 	reader := bufio.NewReader(file)
-
-	line, _, err := reader.ReadLine()
-	if err != nil {
-		return nil, fmt.Errorf("ReadTextLines: %v", err)
-	}
-	nn, err := strconv.ParseInt(string(line), 10, 32)
-	if err != nil {
-		return nil, fmt.Errorf("ReadTextLines: %v", err)
-	}
-
-	f64 := func(s string) float64 {
-		f, err := strconv.ParseFloat(s, 64)
+	ln := 0
+	readLine := func() (string, error) {
+		ln++
+		line, _, err := reader.ReadLine()
 		if err != nil {
-			panic("ReadAlgomesh f64")
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return "", &FormatError{fp, ln, err}
 		}
-		return f
+		return strings.TrimSpace(string(line)), nil
 	}
-	i64 := func(s string) int {
-		i, err := strconv.ParseInt(s, 10, 32)
+	count := func() (int, error) {
+		line, err := readLine()
 		if err != nil {
-			panic("ReadAlgomesh i64")
+			return 0, err
 		}
-		if i < 0 {
-			panic("wtf")
+		n, err := strconv.ParseInt(line, 10, 32)
+		if err != nil || n < 0 {
+			return 0, &FormatError{fp, ln, fmt.Errorf("invalid count: %s", line)}
 		}
-		return int(i)
+		return int(n), nil
 	}
 
+	nn, err := count()
+	if err != nil {
+		return nil, err
+	}
 	nds := make([][]float64, nn)
-	for i := 0; i < int(nn); i++ {
-		line, _, err := reader.ReadLine()
+	for i := range nn {
+		line, err := readLine()
 		if err != nil {
-			return nil, fmt.Errorf("ReadTextLines: %v", err)
+			return nil, err
+		}
+		sp := strings.Fields(line)
+		if len(sp) != 2 && len(sp) != 3 {
+			return nil, &FormatError{fp, ln, fmt.Errorf("node %d: %d coordinates, expecting 2 or 3", i+1, len(sp))}
 		}
-		sp := strings.Split(string(line), " ")
-		switch len(sp) {
-		case 3:
-			nds[i] = []float64{f64(sp[0]), f64(sp[1]), f64(sp[2])}
-		case 2:
-			nds[i] = []float64{f64(sp[0]), f64(sp[1])}
-		default:
-			panic("ReadAlgomesh 1")
+		nds[i] = make([]float64, len(sp))
+		for j, v := range sp {
+			if nds[i][j], err = strconv.ParseFloat(v, 64); err != nil {
+				return nil, &FormatError{fp, ln, fmt.Errorf("node %d: %v", i+1, err)}
+			}
 		}
 	}
 
-	line, _, err = reader.ReadLine()
-	if err != nil {
-		return nil, fmt.Errorf("ReadTextLines: %v", err)
-	}
-	ne, err := strconv.ParseInt(string(line), 10, 32)
+	ne, err := count()
 	if err != nil {
-		return nil, fmt.Errorf("ReadTextLines: %v", err)
+		return nil, err
 	}
-
 	els := make([][]int, ne)
 	ex := 0.
 	checkLngest := func(ni []int) {
@@ -124,21 +133,41 @@ func ReadAlgomesh(fp string, prnt bool) (*Slice, error) {
 		chk(nds[ni[0]], nds[ni[2]])
 		chk(nds[ni[2]], nds[ni[1]])
 	}
-	for i := 0; i < int(ne); i++ {
-		line, _, err := reader.ReadLine()
+	for i := range ne {
+		line, err := readLine()
 		if err != nil {
-			return nil, fmt.Errorf("ReadTextLines: %v", err)
+			return nil, err
 		}
-		sp := strings.Split(string(line), " ")
+		sp := strings.Fields(line)
 		if len(sp) != 3 {
-			panic("ReadAlgomesh assumes triagular meshes for now")
+			return nil, &FormatError{fp, ln, fmt.Errorf("element %d: %d nodes, only triangular meshes are supported", i+1, len(sp))}
+		}
+		els[i] = make([]int, 3)
+		for j, v := range sp {
+			n, err := strconv.ParseInt(v, 10, 32)
+			if err != nil {
+				return nil, &FormatError{fp, ln, fmt.Errorf("element %d: %v", i+1, err)}
+			}
+			if n < 1 || int(n) > nn {
+				return nil, &FormatError{fp, ln, fmt.Errorf("element %d: node %d out of range (%d nodes)", i+1, n, nn)}
+			}
+			els[i][j] = int(n) - 1
 		}
-		els[i] = []int{i64(sp[0]) - 1, i64(sp[1]) - 1, i64(sp[2]) - 1}
 		checkLngest(els[i])
 	}
 
-	if _, _, err := reader.ReadLine(); err != io.EOF {
-		panic("ReadAlgomesh 3")
+	for {
+		line, _, err := reader.ReadLine()
+		if err == io.EOF {
+			break
+		}
+		ln++
+		if err != nil {
+			return nil, &FormatError{fp, ln, err}
+		}
+		if len(strings.TrimSpace(string(line))) > 0 {
+			return nil, &FormatError{fp, ln, fmt.Errorf("unexpected data following %d elements", ne)}
+		}
 	}
 
 	sl := Slice{Name: mmio.FileName(fp, false), Nodes: nds, Elements: els, r: math.Sqrt(ex)}
@@ -0,0 +1,128 @@
+package assimilate
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/maseology/goHydro/internal/rngseed"
+	rr "github.com/maseology/goHydro/rainrun"
+)
+
+// Config of an assimilation run
+type Config struct {
+	Model     string    // registered model name (see rainrun.Models())
+	P         []float64 // model parameters
+	N         int       // ensemble size; default: 50
+	PrecipErr float64   // standard deviation of the log-normal multiplicative precipitation error; default: .25
+	PETErr    float64   // standard deviation of the log-normal multiplicative PET error; default: .1
+	ObsErr    float64   // observation error standard deviation, as a fraction of observed flow; default: .1
+	Seed      int64     // random seed; 0: seeded from the clock (see Result.Seed)
+}
+
+// Step reports the ensemble at a single timestep
+type Step struct {
+	Mean, Spread []float64 // analysed model state: ensemble mean and standard deviation
+	Prior        float64   // ensemble mean runoff prior to assimilation
+	Q, Qspread   float64   // analysed runoff: ensemble mean and standard deviation
+	Assimilated  bool      // false when no observation was available
+}
+
+// Result of an assimilation run
+type Result struct {
+	Steps []Step
+	Final []rr.State // analysed state of each ensemble member at the end of the record, used to initialize forecasts
+	Seed  int64      // random seed used to perturb forcings (and resample particles)
+}
+
+type ensemble struct {
+	cfg Config
+	m   []rr.Model
+	rng *rand.Rand
+}
+
+func newEnsemble(cfg Config, ts float64) (*ensemble, error) {
+	if cfg.N <= 0 {
+		cfg.N = 50
+	}
+	if cfg.N < 2 {
+		return nil, fmt.Errorf("assimilate: ensemble size must be greater than 1")
+	}
+	if cfg.PrecipErr <= 0. {
+		cfg.PrecipErr = .25
+	}
+	if cfg.PETErr <= 0. {
+		cfg.PETErr = .1
+	}
+	if cfg.ObsErr <= 0. {
+		cfg.ObsErr = .1
+	}
+	e := ensemble{cfg: cfg, m: make([]rr.Model, cfg.N)}
+	for i := range e.m {
+		m, err := rr.NewModelAt(cfg.Model, ts, cfg.P...)
+		if err != nil {
+			return nil, err
+		}
+		e.m[i] = m
+	}
+	e.rng, e.cfg.Seed = rngseed.New(e.cfg.Seed)
+	return &e, nil
+}
+
+// lognormal returns a multiplicative error of unit mean
+func (e *ensemble) lognormal(sd float64) float64 {
+	return math.Exp(sd*e.rng.NormFloat64() - sd*sd/2.)
+}
+
+// forecast advances every member by one timestep using perturbed forcing, returning the simulated runoff
+func (e *ensemble) forecast(v *rr.Dset) []float64 {
+	q := make([]float64, len(e.m))
+	for i, m := range e.m {
+		d := v.Perturb(e.lognormal(e.cfg.PrecipErr), e.lognormal(e.cfg.PETErr))
+		_, _, q[i], _ = m.Update(&d)
+	}
+	return q
+}
+
+func (e *ensemble) obsVariance(q float64) float64 {
+	const minsd = 1e-3 // avoids a zero variance at zero flow
+	sd := math.Max(e.cfg.ObsErr*q, minsd)
+	return sd * sd
+}
+
+func (e *ensemble) final() []rr.State {
+	s := make([]rr.State, len(e.m))
+	for i, m := range e.m {
+		s[i] = rr.Snapshot(m)
+	}
+	return s
+}
+
+// stats returns the weighted mean and standard deviation of each column of x
+func stats(x [][]float64, w []float64) (mean, sd []float64) {
+	mean, sd = make([]float64, len(x[0])), make([]float64, len(x[0]))
+	for i, xi := range x {
+		for j, v := range xi {
+			mean[j] += w[i] * v
+		}
+	}
+	for i, xi := range x {
+		for j, v := range xi {
+			sd[j] += w[i] * (v - mean[j]) * (v - mean[j])
+		}
+	}
+	for j := range sd {
+		sd[j] = math.Sqrt(sd[j])
+	}
+	return
+}
+
+func uniform(n int) []float64 {
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = 1. / float64(n)
+	}
+	return w
+}
+
+func observed(q float64) bool { return !math.IsNaN(q) && q >= 0. }
@@ -0,0 +1,94 @@
+package assimilate
+
+import (
+	"math"
+
+	rr "github.com/maseology/goHydro/rainrun"
+)
+
+// EnKF runs an ensemble Kalman filter over the forcing record, assimilating
+// observed runoff (Dset.Q) with perturbed observations after
+// Evensen G., 2003. The Ensemble Kalman Filter: theoretical formulation and practical implementation. Ocean Dynamics 53. pp. 343-367.
+// The state vector is augmented with simulated runoff; only storages are updated,
+// and are kept within their capacity (see rainrun.Bounder).
+func EnKF(frc *rr.Frc, cfg Config) (*Result, error) {
+	e, err := newEnsemble(cfg, frc.Timestep)
+	if err != nil {
+		return nil, err
+	}
+	n := len(e.m)
+	w := uniform(n)
+	res := Result{Steps: make([]Step, len(frc.D)), Seed: e.cfg.Seed}
+	x := make([][]float64, n)
+	for t, v := range frc.D {
+		q := e.forecast(&v)
+		for i, m := range e.m {
+			x[i] = m.State()
+		}
+		st := Step{}
+		qm, qs := stats(col(q), w)
+		st.Prior = qm[0]
+
+		if observed(v.Q) {
+			st.Assimilated = true
+			r := e.obsVariance(v.Q)
+			xm, _ := stats(x, w)
+			// covariance between each state and simulated runoff
+			cxy, vyy := make([]float64, len(xm)), 0.
+			for i := range n {
+				dy := q[i] - qm[0]
+				for j := range xm {
+					cxy[j] += (x[i][j] - xm[j]) * dy
+				}
+				vyy += dy * dy
+			}
+			for j := range cxy {
+				cxy[j] /= float64(n - 1)
+			}
+			vyy /= float64(n - 1)
+			for i, m := range e.m {
+				inno := v.Q + math.Sqrt(r)*e.rng.NormFloat64() - q[i] // perturbed observation innovation
+				lo, hi := bounds(m, len(x[i]))
+				for j := range x[i] {
+					if math.IsNaN(lo[j]) {
+						continue // not a storage
+					}
+					x[i][j] = min(max(x[i][j]+cxy[j]/(vyy+r)*inno, lo[j]), hi[j])
+				}
+				q[i] = math.Max(0., q[i]+vyy/(vyy+r)*inno)
+				if err := m.SetState(x[i]); err != nil {
+					return nil, err
+				}
+			}
+			qm, qs = stats(col(q), w)
+		}
+		st.Mean, st.Spread = stats(x, w)
+		st.Q, st.Qspread = qm[0], qs[0]
+		res.Steps[t] = st
+	}
+	res.Final = e.final()
+	return &res, nil
+}
+
+// bounds returns the bounds of the n state variables of model m; models not
+// reporting their bounds have all states updated, kept non-negative
+func bounds(m rr.Model, n int) (lo, hi []float64) {
+	if b, ok := m.(rr.Bounder); ok {
+		if lo, hi = b.StateBounds(); len(lo) == n {
+			return
+		}
+	}
+	lo, hi = make([]float64, n), make([]float64, n)
+	for j := range hi {
+		hi[j] = math.Inf(1)
+	}
+	return
+}
+
+func col(v []float64) [][]float64 {
+	c := make([][]float64, len(v))
+	for i := range v {
+		c[i] = []float64{v[i]}
+	}
+	return c
+}
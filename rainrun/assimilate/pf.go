@@ -0,0 +1,85 @@
+package assimilate
+
+import (
+	"math"
+
+	rr "github.com/maseology/goHydro/rainrun"
+)
+
+// ParticleFilter runs a sequential importance resampling (SIR) particle filter
+// over the forcing record, assimilating observed runoff (Dset.Q) assuming
+// Gaussian observation error. Particles are resampled (systematic) when the
+// effective sample size falls below half the ensemble size.
+func ParticleFilter(frc *rr.Frc, cfg Config) (*Result, error) {
+	e, err := newEnsemble(cfg, frc.Timestep)
+	if err != nil {
+		return nil, err
+	}
+	n := len(e.m)
+	w := uniform(n)
+	res := Result{Steps: make([]Step, len(frc.D)), Seed: e.cfg.Seed}
+	x := make([][]float64, n)
+	for t, v := range frc.D {
+		q := e.forecast(&v)
+		st := Step{}
+		qm, _ := stats(col(q), w)
+		st.Prior = qm[0]
+
+		if observed(v.Q) {
+			st.Assimilated = true
+			r := e.obsVariance(v.Q)
+			lw, lmax := make([]float64, n), math.Inf(-1)
+			for i := range n {
+				lw[i] = math.Log(w[i]) - (v.Q-q[i])*(v.Q-q[i])/2./r
+				lmax = math.Max(lmax, lw[i])
+			}
+			sw := 0.
+			for i := range n {
+				w[i] = math.Exp(lw[i] - lmax)
+				sw += w[i]
+			}
+			neff := 0.
+			for i := range n {
+				w[i] /= sw
+				neff += w[i] * w[i]
+			}
+			if 1./neff < float64(n)/2. {
+				if err := e.resample(w, q); err != nil {
+					return nil, err
+				}
+				w = uniform(n)
+			}
+		}
+		for i, m := range e.m {
+			x[i] = m.State()
+		}
+		qm, qs := stats(col(q), w)
+		st.Mean, st.Spread = stats(x, w)
+		st.Q, st.Qspread = qm[0], qs[0]
+		res.Steps[t] = st
+	}
+	res.Final = e.final()
+	return &res, nil
+}
+
+// resample replaces the ensemble states by systematic resampling
+func (e *ensemble) resample(w, q []float64) error {
+	n := len(w)
+	s, qq := make([][]float64, n), make([]float64, n)
+	u, c, j := e.rng.Float64()/float64(n), w[0], 0
+	for i := range n {
+		for u > c && j < n-1 {
+			j++
+			c += w[j]
+		}
+		s[i], qq[i] = e.m[j].State(), q[j]
+		u += 1. / float64(n)
+	}
+	for i, m := range e.m {
+		if err := m.SetState(s[i]); err != nil {
+			return err
+		}
+	}
+	copy(q, qq)
+	return nil
+}
@@ -9,8 +9,10 @@ import (
 // original ref: Atkinson S.E., R.A. Woods, M. Sivapalan, 2002. Climate and landscape controls on water balance model complexity over changing timescales. Water Resource Research 38(12): 1314.
 // additional ref: Wittenberg H., M. Sivapalan, 1999. Watershed groundwater balance equation using streamflow recession analysis and baseflow separation. Journal of Hydrology 219, pp.20-33.
 // sto: current storage; sint current interception storage; cov: fractional forest cover; kb = 1/Tcbf
+// nsub: number of hourly sub-steps per timestep (default 24, i.e., daily)
 type Atkinson struct {
 	sto, sint, sintc, cov, kb, a, b, sbc, sfc float64
+	nsub                                      int
 }
 
 // New Atkinson constructor
@@ -27,6 +29,12 @@ func (m *Atkinson) New(p ...float64) {
 	m.kb = p[4]            // baseflow recession coefficient
 	m.a = p[5]             // sub-surface flow coefficient (S=aQ^b - Wittenberg and Sivapalan, 1999)
 	m.b = 1. / (1. - p[6]) // sub-surface flow coefficient [0,1]; reciprocal taken here as opposed to in Update method
+	m.nsub = 24
+}
+
+// SetTimestep sets the number of hourly sub-steps per timestep ts [s]
+func (m *Atkinson) SetTimestep(ts float64) {
+	m.nsub = max(int(math.Round(ts/3600.)), 1)
 }
 
 // Storage returns total storage
@@ -34,11 +42,12 @@ func (m *Atkinson) Storage() float64 {
 	return m.sto + m.sint
 }
 
-// Update state for daily inputs
+// Update state, sub-stepped at hourly intervals
 func (m *Atkinson) Update(p, ep float64) (float64, float64, float64) {
 	var a, q, g float64
-	ph, eph := p/24., ep/24.
-	for i := 0; i < 24; i++ {
+	n := float64(m.nsub)
+	ph, eph := p/n, ep/n
+	for i := 0; i < m.nsub; i++ {
 		a1, q1, g1 := m.UpdateHourly(ph, eph)
 		a += a1
 		q += q1
@@ -111,3 +120,22 @@ func (m *Atkinson) UpdateHourly(p, ep float64) (float64, float64, float64) {
 
 // // Ndim returns the number of dimensions
 // func (m *Atkinson) Ndim() int { return 7 }
+
+// State returns the model state: [sto, sint]
+func (m *Atkinson) State() []float64 {
+	return []float64{m.sto, m.sint}
+}
+
+// SetState restores the model state returned by State()
+func (m *Atkinson) SetState(x []float64) error {
+	if err := checkState("Atkinson", x, 2); err != nil {
+		return err
+	}
+	m.sto, m.sint = x[0], x[1]
+	return nil
+}
+
+// StateBounds returns the bounds of the state variables returned by State()
+func (m *Atkinson) StateBounds() (lo, hi []float64) {
+	return storages(m.sbc, m.sintc)
+}
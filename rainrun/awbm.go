@@ -0,0 +1,71 @@
+package rainrun
+
+import "math"
+
+// AWBM Australian Water Balance Model
+// ref: Boughton, W.C., 2004. The Australian water balance model. Environmental Modelling & Software 19. pp. 943-956.
+type AWBM struct {
+	s      [3]res // partial area surface stores
+	a      [3]float64
+	sr, br res // surface routing and baseflow stores
+	bfi    float64
+}
+
+// New AWBM constructor
+// [c1, c2, c3, a1, a2, a3, bfi, kbase, ksurf]
+// partial areas (a1, a2, a3) are normalized to sum to 1
+func (m *AWBM) New(p ...float64) {
+	sa := p[3] + p[4] + p[5]
+	if sa <= 0. || fracCheck(p[6]) || fracCheck(p[7]) || fracCheck(p[8]) {
+		panic("AWBM input error")
+	}
+	for i := range 3 {
+		m.s[i].new(p[i], 0.) // surface store capacity
+		m.a[i] = p[3+i] / sa // partial area fraction
+	}
+	m.bfi = p[6]          // baseflow index
+	m.br.new(0., 1.-p[7]) // baseflow recession constant (fraction retained per timestep)
+	m.sr.new(0., 1.-p[8]) // surface runoff recession constant (fraction retained per timestep)
+}
+
+// Update state for daily inputs
+func (m *AWBM) Update(p, ep float64) (float64, float64, float64) {
+	a, x := 0., 0.
+	for i := range m.s {
+		ai := math.Min(ep, m.s[i].sto+p)
+		a += m.a[i] * ai
+		x += m.a[i] * m.s[i].overflow(p-ai) // saturation excess
+	}
+	g := m.bfi * x
+	m.br.update(g)
+	m.sr.update(x - g)
+	return a, m.sr.decayExp() + m.br.decayExp(), g
+}
+
+// Storage returns total storage
+func (m *AWBM) Storage() float64 {
+	s := m.sr.sto + m.br.sto
+	for i := range m.s {
+		s += m.a[i] * m.s[i].sto
+	}
+	return s
+}
+
+// State returns the model state: [s1, s2, s3, surface, baseflow]
+func (m *AWBM) State() []float64 {
+	return []float64{m.s[0].sto, m.s[1].sto, m.s[2].sto, m.sr.sto, m.br.sto}
+}
+
+// SetState restores the model state returned by State()
+func (m *AWBM) SetState(x []float64) error {
+	if err := checkState("AWBM", x, 5); err != nil {
+		return err
+	}
+	m.s[0].sto, m.s[1].sto, m.s[2].sto, m.sr.sto, m.br.sto = x[0], x[1], x[2], x[3], x[4]
+	return nil
+}
+
+// StateBounds returns the bounds of the state variables returned by State()
+func (m *AWBM) StateBounds() (lo, hi []float64) {
+	return storages(m.s[0].cap, m.s[1].cap, m.s[2].cap, unbounded, unbounded)
+}
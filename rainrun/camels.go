@@ -0,0 +1,153 @@
+package rainrun
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoadCAMELS reads a CAMELS (Newman et.al., 2015; Addor et.al., 2017) catchment:
+// a daily basin-mean forcing file (e.g., *_lump_cida_forcing_leap.txt) and,
+// optionally, a streamflow file (*_streamflow_qc.txt). Latitude, elevation and
+// catchment area are read from the forcing file header and set on the Frc in
+// place of those of the Loader (which is left unchanged); precipitation is
+// partitioned about Loader.Tcrit, PET is computed using the Loader's PET
+// estimator and flows [cfs] are converted to mm/d.
+func (ld *Loader) LoadCAMELS(forcingfp, flowfp string) (*Frc, error) {
+	ld = ld.local()
+	if ld.Timestep != 86400. {
+		return nil, fmt.Errorf("rainrun.LoadCAMELS: CAMELS forcings are daily")
+	}
+	f, err := os.Open(forcingfp)
+	if err != nil {
+		return nil, fmt.Errorf("rainrun.LoadCAMELS: %v", err)
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	var hdr [3]float64 // latitude, elevation, area [m²]
+	for i := range hdr {
+		if !sc.Scan() {
+			return nil, fmt.Errorf("rainrun.LoadCAMELS %s: incomplete header", forcingfp)
+		}
+		if hdr[i], err = strconv.ParseFloat(strings.TrimSpace(sc.Text()), 64); err != nil {
+			return nil, fmt.Errorf("rainrun.LoadCAMELS %s: header line %d: %v", forcingfp, i+1, err)
+		}
+	}
+	if !sc.Scan() {
+		return nil, fmt.Errorf("rainrun.LoadCAMELS %s: column header missing", forcingfp)
+	}
+	ic := map[string]int{}
+	for i, s := range strings.Fields(sc.Text()) {
+		ic[strings.Split(s, "(")[0]] = i
+	}
+	for _, s := range []string{"Year", "Mnth", "Day", "dayl", "prcp", "srad", "tmax", "tmin"} {
+		if _, ok := ic[s]; !ok {
+			return nil, fmt.Errorf("rainrun.LoadCAMELS %s: column %s not found", forcingfp, s)
+		}
+	}
+	ld.Latitude, ld.Elevation, ld.Cakm2 = hdr[0], hdr[1], hdr[2]/1e6
+
+	dt, rows := []time.Time{}, []row{}
+	for ln := 5; sc.Scan(); ln++ {
+		sp := strings.Fields(sc.Text())
+		if len(sp) == 0 {
+			continue
+		}
+		if len(sp) < len(ic) {
+			return nil, fmt.Errorf("rainrun.LoadCAMELS %s: line %d: %d fields, expecting %d", forcingfp, ln, len(sp), len(ic))
+		}
+		t, err := camelsDate(sp[ic["Year"]], sp[ic["Mnth"]], sp[ic["Day"]])
+		if err != nil {
+			return nil, fmt.Errorf("rainrun.LoadCAMELS %s: line %d: %v", forcingfp, ln, err)
+		}
+		var v row
+		for c, s := range map[Column]string{ColPrecip: "prcp", ColTx: "tmax", ColTn: "tmin", ColKg: "srad"} {
+			if v[c], err = ld.parse(sp[ic[s]]); err != nil {
+				return nil, fmt.Errorf("rainrun.LoadCAMELS %s: line %d: %v", forcingfp, ln, err)
+			}
+		}
+		dayl, err := ld.parse(sp[ic["dayl"]])
+		if err != nil {
+			return nil, fmt.Errorf("rainrun.LoadCAMELS %s: line %d: %v", forcingfp, ln, err)
+		}
+		v[ColKg] *= dayl / 1e6 // mean daylight W/m² to MJ/m²/d
+		v[ColFlow] = math.NaN()
+		dt = append(dt, t)
+		rows = append(rows, v)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("rainrun.LoadCAMELS %s: %v", forcingfp, err)
+	}
+
+	if flowfp != "" {
+		q, err := ld.camelsFlow(flowfp, hdr[2])
+		if err != nil {
+			return nil, err
+		}
+		for i, t := range dt {
+			if v, ok := q[t]; ok {
+				rows[i][ColFlow] = v
+			}
+		}
+	}
+
+	has := [ncol]bool{ColFlow: true, ColTx: true, ColTn: true, ColPrecip: true, ColKg: true}
+	frc, err := ld.build(dt, rows, has)
+	if err != nil {
+		return nil, fmt.Errorf("rainrun.LoadCAMELS %s: %v", forcingfp, err)
+	}
+	frc.FilePath = forcingfp
+	return frc, nil
+}
+
+// camelsFlow reads a CAMELS streamflow file: "gauge year month day Q(cfs) flag"; returns mm/d
+func (ld *Loader) camelsFlow(fp string, aream2 float64) (map[time.Time]float64, error) {
+	const cfs2cms = .0283168466
+	f, err := os.Open(fp)
+	if err != nil {
+		return nil, fmt.Errorf("rainrun.LoadCAMELS: %v", err)
+	}
+	defer f.Close()
+
+	q := make(map[time.Time]float64)
+	sc := bufio.NewScanner(f)
+	for ln := 1; sc.Scan(); ln++ {
+		sp := strings.Fields(sc.Text())
+		if len(sp) == 0 {
+			continue
+		}
+		if len(sp) < 5 {
+			return nil, fmt.Errorf("rainrun.LoadCAMELS %s: line %d: %d fields, expecting 6", fp, ln, len(sp))
+		}
+		t, err := camelsDate(sp[1], sp[2], sp[3])
+		if err != nil {
+			return nil, fmt.Errorf("rainrun.LoadCAMELS %s: line %d: %v", fp, ln, err)
+		}
+		v, err := ld.parse(sp[4])
+		if err != nil {
+			return nil, fmt.Errorf("rainrun.LoadCAMELS %s: line %d: %v", fp, ln, err)
+		}
+		if v < 0. {
+			v = math.NaN()
+		}
+		q[t] = v * cfs2cms * 86400. / aream2 * 1000. // cfs to mm/d
+	}
+	return q, sc.Err()
+}
+
+func camelsDate(y, m, d string) (time.Time, error) {
+	var ymd [3]int
+	for i, s := range []string{y, m, d} {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return time.Time{}, err
+		}
+		ymd[i] = v
+	}
+	return time.Date(ymd[0], time.Month(ymd[1]), ymd[2], 0, 0, 0, 0, time.UTC), nil
+}
@@ -0,0 +1,164 @@
+package rainrun
+
+import (
+	"github.com/maseology/goHydro/pet"
+	"github.com/maseology/goHydro/snowpack"
+)
+
+// Snow : interface to snowpack models coupled to a Lumper
+type Snow interface {
+	New(p ...float64)
+	Update(d *Dset) (float64, error) // returns yield (melt + throughfall)
+	Storage() float64                // snow water equivalent
+	Stater
+}
+
+// PET : interface to potential evapotranspiration estimators coupled to a Lumper
+type PET interface {
+	New(p ...float64)
+	Evaporation(d *Dset) float64
+}
+
+// SnowInfo holds the metadata of a registered snowpack model
+type SnowInfo struct {
+	Name   string
+	Params []Param
+	New    func() Snow
+}
+
+// PETInfo holds the metadata of a registered PET estimator
+type PETInfo struct {
+	Name   string
+	Params []Param
+	New    func() PET
+}
+
+const (
+	denscoef = 1.      // snowpack densification coefficient
+	pres     = 101300. // atmospheric pressure [Pa]
+)
+
+var builtinSnow = []SnowInfo{
+	{
+		Name: "CCF",
+		New:  func() Snow { return &CCFSnow{} },
+		Params: []Param{
+			{Name: "tindex", Unit: "m/°C/d", Desc: "cold-content factor temperature index", Lower: .0002, Upper: .05, Default: .00035, Log: true},
+			{Name: "ddfc", Unit: "-", Desc: "degree-day factor adjustment based on pack density", Lower: 0., Upper: 10., Default: 1.1},
+			{Name: "baseT", Unit: "°C", Desc: "base/critical temperature", Lower: -5., Upper: 5., Default: 0.},
+			{Name: "tsf", Unit: "-", Desc: "surface temperature factor", Lower: .1, Upper: .7, Default: .5},
+		},
+	},
+	{
+		Name: "DDF",
+		New:  func() Snow { return &DDFSnow{} },
+		Params: []Param{
+			{Name: "ddfc", Unit: "-", Desc: "degree-day factor adjustment based on pack density", Lower: 0., Upper: 10., Default: 1.1},
+			{Name: "baseT", Unit: "°C", Desc: "base/critical temperature", Lower: -5., Upper: 5., Default: 0.},
+		},
+	},
+}
+
+var builtinPET = []PETInfo{
+	{
+		Name: "Makkink",
+		New:  func() PET { return &MakkinkPET{} },
+		Params: []Param{
+			{Name: "alpha", Unit: "-", Desc: "Makkink coefficient", Lower: 0., Upper: 2.5, Default: .61},
+			{Name: "beta", Unit: "m/d", Desc: "Makkink offset", Lower: -.01, Upper: .003, Default: .001},
+		},
+	},
+	{
+		Name: "Oudin",
+		New:  func() PET { return &OudinPET{} },
+	},
+}
+
+// CCFSnow couples the cold-content factor snowpack model (snowpack.CCF)
+type CCFSnow struct{ snowpack.CCF }
+
+// New CCFSnow constructor
+// [tindex, ddfc, baseT, tsf]
+func (s *CCFSnow) New(p ...float64) {
+	s.CCF = snowpack.NewCCF(p[0], p[1], p[2], p[3], denscoef)
+}
+
+// Update state
+func (s *CCFSnow) Update(d *Dset) (float64, error) {
+	tm := (d.Tx + d.Tn) / 2.
+	m, tf, err := s.CCF.Update(d.rf, d.sf, tm)
+	return m + tf, err
+}
+
+// Storage returns the snow water equivalent
+func (s *CCFSnow) Storage() float64 {
+	_, _, swe, _ := s.Properties()
+	return swe
+}
+
+// StateBounds returns the bounds of the state variables returned by State():
+// only the snow water equivalent is a storage, no less than the liquid water
+// held in the pack; pack properties are left unchanged
+func (s *CCFSnow) StateBounds() (lo, hi []float64) {
+	return fixed([]float64{s.State()[2]}, []float64{unbounded}, 5)
+}
+
+// DDFSnow couples the degree-day factor snowpack model (snowpack.DDF)
+type DDFSnow struct{ snowpack.DDF }
+
+// New DDFSnow constructor
+// [ddfc, baseT]
+func (s *DDFSnow) New(p ...float64) {
+	s.DDF = snowpack.NewDDF(p[0], p[1], denscoef)
+}
+
+// Update state
+func (s *DDFSnow) Update(d *Dset) (float64, error) {
+	tm := (d.Tx + d.Tn) / 2.
+	m, tf := s.DDF.Update(d.rf, d.sf, tm)
+	return m + tf, nil
+}
+
+// Storage returns the snow water equivalent
+func (s *DDFSnow) Storage() float64 {
+	_, _, swe, _ := s.Properties()
+	return swe
+}
+
+// StateBounds returns the bounds of the state variables returned by State():
+// only the snow water equivalent is a storage, no less than the liquid water
+// held in the pack; pack properties are left unchanged
+func (s *DDFSnow) StateBounds() (lo, hi []float64) {
+	return fixed([]float64{s.State()[2]}, []float64{unbounded}, 3)
+}
+
+// MakkinkPET estimates PET from global radiation (Dset.Kg) and mean daily temperature
+type MakkinkPET struct{ alpha, beta float64 }
+
+// New MakkinkPET constructor
+// [alpha, beta]
+func (e *MakkinkPET) New(p ...float64) {
+	e.alpha, e.beta = p[0], p[1]
+}
+
+// Evaporation returns potential evaporation [mm/d], using atmospheric pressure
+// when given (Dset.pa), otherwise a standard atmosphere
+func (e *MakkinkPET) Evaporation(d *Dset) float64 {
+	tm, pa := (d.Tx+d.Tn)/2., d.pa*1000.
+	if !(pa > 0.) {
+		pa = pres
+	}
+	return pet.Makkink(d.Kg, tm, pa, e.alpha, e.beta) * 1000.
+}
+
+// OudinPET estimates PET from global radiation (Dset.Kg) and mean daily temperature
+type OudinPET struct{}
+
+// New OudinPET constructor (parameterless)
+func (e *OudinPET) New(p ...float64) {}
+
+// Evaporation returns potential evaporation [mm/d]
+func (e *OudinPET) Evaporation(d *Dset) float64 {
+	tm := (d.Tx + d.Tn) / 2.
+	return pet.Oudin(d.Kg, tm) * 1000.
+}
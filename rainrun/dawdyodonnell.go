@@ -62,3 +62,22 @@ func (m *DawdyODonnell) Storage() float64 {
 
 // // Ndim returns the number of dimensions
 // func (m *DawdyODonnell) Ndim() int { return 6 }
+
+// State returns the model state: [depint, upsz, ores, gwres]
+func (m *DawdyODonnell) State() []float64 {
+	return []float64{m.depint.sto, m.upsz.sto, m.ores.sto, m.gwres.sto}
+}
+
+// SetState restores the model state returned by State()
+func (m *DawdyODonnell) SetState(x []float64) error {
+	if err := checkState("DawdyODonnell", x, 4); err != nil {
+		return err
+	}
+	m.depint.sto, m.upsz.sto, m.ores.sto, m.gwres.sto = x[0], x[1], x[2], x[3]
+	return nil
+}
+
+// StateBounds returns the bounds of the state variables returned by State()
+func (m *DawdyODonnell) StateBounds() (lo, hi []float64) {
+	return storages(m.depint.cap, m.upsz.cap, unbounded, m.gwres.cap)
+}
@@ -0,0 +1,185 @@
+package ensemble
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/maseology/goHydro/rainrun/optimize"
+)
+
+// Combination is a weighted combination of the ensemble members
+type Combination struct {
+	Method       string
+	W            []float64 // member weights, summing to 1
+	Sigma        []float64 // BMA only: member kernel standard deviations [mm/ts]
+	Q            []float64 // combined hydrograph [mm/ts]
+	Lower, Upper []float64 // BMA only: predictive interval bounds [mm/ts]
+	Niter        int       // BMA only: number of EM iterations
+	LogLik       float64   // BMA only: log-likelihood of the scored timesteps
+}
+
+// Average returns the equally-weighted mean of the members
+func (e *Ensemble) Average() *Combination {
+	w := make([]float64, len(e.Members))
+	for k := range w {
+		w[k] = 1. / float64(len(w))
+	}
+	return e.combine("average", w)
+}
+
+// Weighted returns the members weighted by their performance: weights are
+// proportional to the inverse of the objective function value (see
+// optimize.Objective), computed over the scored timesteps.
+func (e *Ensemble) Weighted(obj optimize.Objective) (*Combination, error) {
+	const eps = 1e-6 // caps the weight of a perfect fit
+	w, sw := make([]float64, len(e.Members)), 0.
+	for k, s := range e.Sim {
+		f := obj.F(e.observed(s))
+		if math.IsNaN(f) || f < 0. {
+			return nil, fmt.Errorf("ensemble.Weighted: %s of member %s is invalid (%f)", obj.Name, e.Members[k].Name, f)
+		}
+		w[k] = 1. / math.Max(f, eps)
+		sw += w[k]
+	}
+	for k := range w {
+		w[k] /= sw
+	}
+	return e.combine("weighted-"+obj.Name, w), nil
+}
+
+// BMA returns the Bayesian Model Average (Raftery et.al., 2005) of the members.
+// Each member is given a Gaussian kernel centred on its simulation; weights and
+// kernel variances are fit to the scored timesteps by Expectation-Maximization.
+// Lower and Upper bound the ci (e.g., 0.9) predictive interval of the mixture,
+// truncated at zero.
+func (e *Ensemble) BMA(ci float64) (*Combination, error) {
+	const (
+		tol   = 1e-8 // relative change in log-likelihood signalling convergence
+		nitr  = 10000
+		small = 1e-12 // variance floor
+	)
+	if ci <= 0. || ci >= 1. {
+		return nil, fmt.Errorf("ensemble.BMA: interval %f must be in (0,1)", ci)
+	}
+	nk := len(e.Members)
+	var o []float64
+	s := make([][]float64, nk)
+	for k := range e.Sim {
+		o, s[k] = e.observed(e.Sim[k])
+	}
+	n := len(o)
+
+	w, v := make([]float64, nk), make([]float64, nk)
+	for k := range w {
+		w[k] = 1. / float64(nk)
+		for t := range o {
+			v[k] += (o[t] - s[k][t]) * (o[t] - s[k][t])
+		}
+		v[k] = math.Max(v[k]/float64(n), small)
+	}
+
+	z, ll, it := make([][]float64, nk), math.Inf(-1), 0
+	for k := range z {
+		z[k] = make([]float64, n)
+	}
+	for it = 1; it <= nitr; it++ {
+		// E-step
+		ll1 := 0.
+		for t := range o {
+			sz := 0.
+			for k := range z {
+				z[k][t] = w[k] * gauss(o[t], s[k][t], v[k])
+				sz += z[k][t]
+			}
+			if sz <= 0. {
+				for k := range z {
+					z[k][t] = 1. / float64(nk)
+				}
+				ll1 += math.Log(math.SmallestNonzeroFloat64)
+				continue
+			}
+			for k := range z {
+				z[k][t] /= sz
+			}
+			ll1 += math.Log(sz)
+		}
+
+		// M-step
+		for k := range w {
+			sz, ss := 0., 0.
+			for t := range o {
+				sz += z[k][t]
+				ss += z[k][t] * (o[t] - s[k][t]) * (o[t] - s[k][t])
+			}
+			w[k] = sz / float64(n)
+			if sz > 0. {
+				v[k] = math.Max(ss/sz, small)
+			}
+		}
+
+		if math.Abs(ll1-ll) <= tol*math.Abs(ll1) {
+			ll = ll1
+			break
+		}
+		ll = ll1
+	}
+	if it > nitr {
+		it = nitr
+	}
+
+	c := e.combine("BMA", w)
+	c.Niter, c.LogLik = it, ll
+	c.Sigma = make([]float64, nk)
+	for k := range v {
+		c.Sigma[k] = math.Sqrt(v[k])
+	}
+	c.Lower, c.Upper = make([]float64, len(c.Q)), make([]float64, len(c.Q))
+	mu := make([]float64, nk)
+	for t := range c.Q {
+		for k := range mu {
+			mu[k] = e.Sim[k][t]
+		}
+		c.Lower[t] = math.Max(quantile((1.-ci)/2., w, mu, c.Sigma), 0.)
+		c.Upper[t] = math.Max(quantile((1.+ci)/2., w, mu, c.Sigma), 0.)
+	}
+	return c, nil
+}
+
+func (e *Ensemble) combine(method string, w []float64) *Combination {
+	q := make([]float64, len(e.Obs))
+	for k, s := range e.Sim {
+		for t, v := range s {
+			q[t] += w[k] * v
+		}
+	}
+	return &Combination{Method: method, W: w, Q: q}
+}
+
+func gauss(x, mu, v float64) float64 {
+	return math.Exp(-(x-mu)*(x-mu)/2./v) / math.Sqrt(2.*math.Pi*v)
+}
+
+// quantile of a Gaussian mixture, found by bisection
+func quantile(p float64, w, mu, sig []float64) float64 {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for k := range w {
+		lo = math.Min(lo, mu[k]-8.*sig[k])
+		hi = math.Max(hi, mu[k]+8.*sig[k])
+	}
+	cdf := func(x float64) float64 {
+		f := 0.
+		for k := range w {
+			f += w[k] * .5 * math.Erfc(-(x-mu[k])/sig[k]/math.Sqrt2)
+		}
+		return f
+	}
+	for i := 0; i < 100 && hi-lo > 1e-9*math.Max(1., math.Abs(hi)); i++ {
+		x := (lo + hi) / 2.
+		if cdf(x) < p {
+			lo = x
+		} else {
+			hi = x
+		}
+	}
+	return (lo + hi) / 2.
+}
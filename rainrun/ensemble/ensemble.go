@@ -0,0 +1,177 @@
+package ensemble
+
+import (
+	"fmt"
+	"sync"
+
+	rr "github.com/maseology/goHydro/rainrun"
+	"github.com/maseology/goHydro/rainrun/optimize"
+	"github.com/maseology/mmio"
+)
+
+// Member is a calibrated model of the ensemble
+type Member struct {
+	Name  string    // label, defaults to Model
+	Model string    // registered (or coupled) model name, see rainrun.Models()
+	P     []float64 // parameters, at the forcing timestep
+}
+
+// FromResult returns the optimum of a calibration run as an ensemble member
+func FromResult(res *optimize.Result) Member {
+	return Member{Name: res.Model, Model: res.Model, P: res.Best.P}
+}
+
+// Ensemble holds the simulated runoff of every member over a common forcing record
+type Ensemble struct {
+	Members []Member
+	Sim     [][]float64 // [member][timestep] simulated runoff
+	Obs     []float64   // observed runoff
+	Warmup  int         // timesteps excluded from scoring and weighting
+	frc     *rr.Frc
+	msk     rr.Mask
+}
+
+// Run simulates every member concurrently over the forcing record
+func Run(frc *rr.Frc, warmup int, members ...Member) (*Ensemble, error) {
+	if len(members) < 2 {
+		return nil, fmt.Errorf("ensemble.Run: at least 2 members required, %d given", len(members))
+	}
+	if warmup >= len(frc.D)-1 {
+		return nil, fmt.Errorf("ensemble.Run: warm-up of %d exceeds the %d timesteps of forcing data", warmup, len(frc.D))
+	}
+	e := Ensemble{
+		Members: make([]Member, len(members)),
+		Sim:     make([][]float64, len(members)),
+		Obs:     make([]float64, len(frc.D)),
+		Warmup:  warmup,
+		frc:     frc,
+		msk:     frc.Mask(),
+	}
+	for i, m := range members {
+		if m.Name == "" {
+			m.Name = m.Model
+		}
+		e.Members[i] = m
+	}
+	for t, v := range frc.D {
+		e.Obs[t] = v.Q
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(members))
+	for k, mb := range e.Members {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m, err := rr.NewModelAt(mb.Model, frc.Timestep, mb.P...)
+			if err != nil {
+				errs <- fmt.Errorf("ensemble.Run: member %s: %v", mb.Name, err)
+				return
+			}
+			s := make([]float64, len(frc.D))
+			for t, v := range frc.D {
+				_, _, r, _ := m.Update(&v)
+				s[t] = r
+			}
+			e.Sim[k] = s
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+	if e.msk[warmup:].Count() < 2 {
+		return nil, fmt.Errorf("ensemble.Run: fewer than 2 observed timesteps following the warm-up")
+	}
+	return &e, nil
+}
+
+// observed returns the observed and simulated runoff of the scored timesteps: post warm-up and observed
+func (e *Ensemble) observed(s []float64) (o, ss []float64) {
+	return e.msk[e.Warmup:].Apply(e.Obs[e.Warmup:], s[e.Warmup:])
+}
+
+// Scores returns the objective function values of every member, [member][objective]
+func (e *Ensemble) Scores(objs ...optimize.Objective) [][]float64 {
+	f := make([][]float64, len(e.Members))
+	for k, s := range e.Sim {
+		f[k] = score(e, s, objs)
+	}
+	return f
+}
+
+func score(e *Ensemble, s []float64, objs []optimize.Objective) []float64 {
+	o, ss := e.observed(s)
+	f := make([]float64, len(objs))
+	for i, obj := range objs {
+		f[i] = obj.F(o, ss)
+	}
+	return f
+}
+
+// Coverage returns the data coverage of the scored (post warm-up) period
+func (e *Ensemble) Coverage() rr.Mask { return e.msk[e.Warmup:] }
+
+// Report summarizes the scores of every member and combination
+func (e *Ensemble) Report(objs []optimize.Objective, cs ...*Combination) string {
+	s := fmt.Sprintf("ensemble of %d members, scored on %v\n", len(e.Members), e.Coverage())
+	s += fmt.Sprintf("%24s %8s", "member", "weight")
+	for _, o := range objs {
+		s += fmt.Sprintf(" %10s", o.Name)
+	}
+	s += "\n"
+	line := func(name string, w float64, f []float64) {
+		s += fmt.Sprintf("%24s %8.4f", name, w)
+		for _, v := range f {
+			s += fmt.Sprintf(" %10.4f", v)
+		}
+		s += "\n"
+	}
+	fm := e.Scores(objs...)
+	for k, m := range e.Members {
+		w := 0.
+		if len(cs) > 0 {
+			w = cs[0].W[k]
+		}
+		line(m.Name, w, fm[k])
+	}
+	for _, c := range cs {
+		line(c.Method, 1., score(e, c.Q, objs))
+	}
+	return s
+}
+
+// WriteCSV saves the observed, member and combined hydrographs
+func (e *Ensemble) WriteCSV(fp string, cs ...*Combination) {
+	n := len(e.Obs)
+	col := func(v []float64) []interface{} {
+		c := make([]interface{}, n)
+		for i, x := range v {
+			c[i] = x
+		}
+		return c
+	}
+	idt := make([]interface{}, n)
+	for i := range idt {
+		if i < len(e.frc.DT) {
+			idt[i] = e.frc.DT[i]
+		} else {
+			idt[i] = i
+		}
+	}
+	hdr, cols := "date,obs", [][]interface{}{idt, col(e.Obs)}
+	for k, m := range e.Members {
+		hdr += "," + m.Name
+		cols = append(cols, col(e.Sim[k]))
+	}
+	for _, c := range cs {
+		hdr += "," + c.Method
+		cols = append(cols, col(c.Q))
+		if c.Lower != nil {
+			hdr += fmt.Sprintf(",%s.lower,%s.upper", c.Method, c.Method)
+			cols = append(cols, col(c.Lower), col(c.Upper))
+		}
+	}
+	mmio.WriteCSV(fp, hdr, cols...)
+}
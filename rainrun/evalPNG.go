@@ -8,16 +8,20 @@ import (
 	"github.com/maseology/objfunc"
 )
 
-// EvalPNG prints model output to a png
-func EvalPNG(m Lumper, frc *Frc, prfx string) string {
+// EvalPNG prints model output to a png; scores exclude the first nwarm timesteps
+// and any gaps in the observed record (see Mask)
+func EvalPNG(m Model, frc *Frc, nwarm int, prfx string) (string, error) {
+	if nwarm < 0 || nwarm >= frc.Ndt {
+		return "", fmt.Errorf("rainrun.EvalPNG: warm-up of %d timesteps given for a %d timestep record", nwarm, frc.Ndt)
+	}
 	o := make([]float64, frc.Ndt)
 	s := make([]float64, frc.Ndt)
 	b := make([]float64, frc.Ndt)
-	ys, es, as, rs, gs, qs := 0., 0., 0., 0., 0., 0.
+	ys, es, as, rs, gs, qs, rso := 0., 0., 0., 0., 0., 0., 0.
+	msk := frc.Mask()
 	tt := time.Now()
 	for i, v := range frc.D {
-		y := v.Yield()
-		a, r, g := m.Update(y, v.Ep)
+		y, a, r, g := m.Update(&v)
 		o[i] = v.Q
 		s[i] = r
 		b[i] = g
@@ -26,18 +30,25 @@ func EvalPNG(m Lumper, frc *Frc, prfx string) string {
 		as += a
 		rs += r
 		gs += g
-		qs += v.Q
+		if msk[i] {
+			qs += v.Q
+			rso += r
+		}
 	}
-	f := 366. / float64(frc.Ndt)
-	stOf := fmt.Sprintf(" KGE: %.3f\tNSE: %.3f\tRMSE: %.6f\tmon-wr2: %.3f\tBias: %.3f\n", objfunc.KGE(o[365:], s[365:]), objfunc.NSE(o[365:], s[365:]), objfunc.RMSE(o[365:], s[365:]), objfunc.Krause(o[365:], s[365:]), objfunc.Bias(o[365:], s[365:]))
-	stSum := fmt.Sprintf(" y: %.3f\tpet: %.3f\taet: %.3f\trch: %.3f\tro: %.3f\tqobs: %.3f\n", ys*f, es*f, as*f, gs*f, rs*f, qs*f)
+	f := frc.StepsPerYear() / float64(frc.Ndt) // annual totals
+	fo := frc.StepsPerYear() / float64(max(msk.Count(), 1))
+	mo, ms := msk[nwarm:].Apply(o[nwarm:], s[nwarm:])
+	stOf := fmt.Sprintf(" KGE: %.3f\tNSE: %.3f\tRMSE: %.6f\tmon-wr2: %.3f\tBias: %.3f\n", objfunc.KGE(mo, ms), objfunc.NSE(mo, ms), objfunc.RMSE(mo, ms), objfunc.Krause(mo, ms), objfunc.Bias(mo, ms))
+	stCov := fmt.Sprintf(" scored on %v\n", msk[nwarm:])
+	stSum := fmt.Sprintf(" y: %.3f\tpet: %.3f\taet: %.3f\trch: %.3f\tro: %.3f\tqobs: %.3f\t(ro: %.3f when observed)\n", ys*f, es*f, as*f, gs*f, rs*f, qs*fo, rso*fo)
 	stElapsed := fmt.Sprintf(" run-time for %d timesteps: %v\n", frc.Ndt, time.Since(tt))
 	fmt.Print(stOf)
+	fmt.Print(stCov)
 	fmt.Print(stSum)
 	fmt.Print(stElapsed)
-	mmplt.ObsSim(prfx+".hyd.png", o[365:], s[365:])
-	mmplt.ObsSimFDC(prfx+".fdc.png", o[365:], s[365:])
+	mmplt.ObsSim(prfx+".hyd.png", mo, ms) // gaps removed
+	mmplt.ObsSimFDC(prfx+".fdc.png", mo, ms)
 	SumHydrograph(frc, o, s, b, prfx)
 	SumMonthly(frc.DT, o, s, frc.Timestep, 1., prfx)
-	return stOf + stSum + stElapsed
+	return stOf + stCov + stSum + stElapsed, nil
 }
@@ -3,7 +3,7 @@ package rainrun
 import (
 	"fmt"
 	"io"
-	"log"
+	"math"
 	"os"
 	"strconv"
 	"time"
@@ -22,47 +22,183 @@ type Frc struct {
 	Timestep float64     // timestep in seconds
 	Ndt      int         // Ndt number of timesteps
 	FilePath string
+
+	Latitude, Cakm2, Elevation float64 // [°], catchment area [km²] and [m]; set by Loader
+}
+
+// StepsPerYear returns the (average) number of timesteps per year; daily when Timestep is not set
+func (f *Frc) StepsPerYear() float64 {
+	if f.Timestep <= 0. {
+		return 365.25
+	}
+	return 365.25 * 86400. / f.Timestep
+}
+
+// Year returns the number of timesteps in a year (e.g., for model warm-up)
+func (f *Frc) Year() int { return int(math.Round(f.StepsPerYear())) }
+
+// FillGaps fills missing (NaN) forcings: precipitation and snowmelt are set to
+// zero, while temperature, pressure, radiation and PET are interpolated linearly
+// (extended at either end); observed flows are left missing. Returns the number
+// of values filled.
+func (f *Frc) FillGaps() int {
+	n := 0
+	for i := range f.D {
+		for _, v := range []*float64{&f.D[i].rf, &f.D[i].sf, &f.D[i].sm} {
+			if math.IsNaN(*v) {
+				*v = 0.
+				n++
+			}
+		}
+	}
+	for _, fld := range []func(d *Dset) *float64{
+		func(d *Dset) *float64 { return &d.Tx },
+		func(d *Dset) *float64 { return &d.Tn },
+		func(d *Dset) *float64 { return &d.pa },
+		func(d *Dset) *float64 { return &d.Kg },
+		func(d *Dset) *float64 { return &d.Ep },
+	} {
+		i0 := -1 // last valid index
+		for i := range f.D {
+			v := *fld(&f.D[i])
+			if math.IsNaN(v) {
+				continue
+			}
+			for j := i0 + 1; j < i; j++ {
+				if i0 < 0 {
+					*fld(&f.D[j]) = v
+				} else {
+					v0 := *fld(&f.D[i0])
+					*fld(&f.D[j]) = v0 + (v-v0)*float64(j-i0)/float64(i-i0)
+				}
+				n++
+			}
+			i0 = i
+		}
+		if i0 >= 0 {
+			for j := i0 + 1; j < len(f.D); j++ {
+				*fld(&f.D[j]) = *fld(&f.D[i0])
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Missing returns the number of timesteps with missing observed flow and with any missing forcing
+func (f *Frc) Missing() (nq, nfrc int) {
+	for _, d := range f.D {
+		if math.IsNaN(d.Q) {
+			nq++
+		}
+		for _, v := range []float64{d.Tx, d.Tn, d.rf, d.sf, d.sm, d.Ep} {
+			if math.IsNaN(v) {
+				nfrc++
+				break
+			}
+		}
+	}
+	return
 }
 
-type Dset struct{ Q, Tx, Tn, rf, sf, sm, pa, Ep float64 }
+type Dset struct{ Q, Tx, Tn, rf, sf, sm, pa, Ep, Kg float64 } // pa: atmospheric pressure [kPa]; Kg: global shortwave radiation [MJ/m²/d]
 
 func (d *Dset) Yield() float64 { return d.rf + d.sm }
 
 func (d *Dset) Runoff() float64 { return d.Q }
 
+func (d *Dset) Precip() float64 { return d.rf + d.sf }
+
+// Perturb returns a copy of the timestep with precipitation (and snowmelt) scaled by fp and PET scaled by fe
+func (d *Dset) Perturb(fp, fe float64) Dset {
+	o := *d
+	o.rf *= fp
+	o.sf *= fp
+	o.sm *= fp
+	o.Ep *= fe
+	return o
+}
+
 // func (d *Dset) DatArray() (tx, tn, r, s float64) { return d.Tx, d.Tn, d.rf, d.sf }
 
-func ReadOWRC(csvfp string, cakm2, latitude float64) ([]time.Time, []Dset) {
-	cms2mmd := 86.4 / cakm2
+// ReadError reports a forcing file that could not be read
+type ReadError struct {
+	File string
+	Line int // 0 when not specific to a line
+	Err  error
+}
+
+func (e *ReadError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("rainrun: %s line %d: %v", e.File, e.Line, e.Err)
+	}
+	return fmt.Sprintf("rainrun: %s: %v", e.File, e.Err)
+}
+
+func (e *ReadError) Unwrap() error { return e.Err }
+
+// ReadOWRC reads daily forcings and flows (m³/s, converted to mm/d) from an OWRC csv file
+func ReadOWRC(csvfp string, cakm2, latitude float64) ([]time.Time, []Dset, error) {
+	return readOWRC(csvfp, "2006-01-02", cakm2, latitude, 86400.)
+}
+
+// ReadOWRCHourly reads hourly forcings and flows (m³/s, converted to mm/h) from an
+// OWRC csv file, dated "yyyy-mm-dd hh:mm"; set Frc.Timestep to 3600.
+func ReadOWRCHourly(csvfp string, cakm2, latitude float64) ([]time.Time, []Dset, error) {
+	return readOWRC(csvfp, "2006-01-02 15:04", cakm2, latitude, 3600.)
+}
+
+// readOWRC reads forcings of timestep ts [s]; radiation (Kg) remains a daily rate [MJ/m²/d]
+func readOWRC(csvfp, layout string, cakm2, latitude, ts float64) ([]time.Time, []Dset, error) {
+	if cakm2 <= 0. {
+		return nil, nil, &ReadError{csvfp, 0, fmt.Errorf("catchment area must be greater than zero, %f given", cakm2)}
+	}
+	cms2mmts := ts / 1000. / cakm2 // m³/s to mm/ts (86.4/cakm2 when daily)
+	df := ts / 86400.              // day factor
 
 	f, err := os.Open(csvfp)
 	if err != nil {
-		log.Fatalf("readOWRC failed: %v\n", err)
+		return nil, nil, &ReadError{csvfp, 0, err}
 	}
 	defer f.Close()
 
 	recs := mmio.LoadCSV(io.Reader(f), 1) // "Date","Flow","Flag","Tx","Tn","Rf","Sf","Sm","Pa"
-	o, ts := make([]Dset, 0), make([]time.Time, 0)
+	fail := func(ln int, err error) ([]time.Time, []Dset, error) {
+		for range recs { // drain the reader
+		}
+		return nil, nil, &ReadError{csvfp, ln, err}
+	}
+	o, dt := make([]Dset, 0), make([]time.Time, 0)
 	si := solirrad.New(latitude, 0., 0.)
+	ln := 1 // header
 	for rec := range recs {
-		// fmt.Println(rec)
-		t, err := time.Parse("2006-01-02", rec[0])
-		doy := t.YearDay()
+		ln++
+		if len(rec) < 8 {
+			return fail(ln, fmt.Errorf("%d fields, expecting at least 8", len(rec)))
+		}
+		t, err := time.Parse(layout, rec[0])
 		if err != nil {
-			log.Fatalf("readOWRC date read fail: %v\n", err)
+			return fail(ln, fmt.Errorf("date parse error: %v", err))
 		}
-		// fmt.Println(t)
+		doy := t.YearDay()
+		var perr error
 		g := func(i int) float64 {
 			v, err := strconv.ParseFloat(rec[i], 64)
 			if err != nil {
 				if rec[i] == "NA" {
+					if i == 1 {
+						return math.NaN() // missing flows are masked during evaluation
+					}
 					return 0. //math.NaN()
 				}
-				log.Fatalf("readOWRC date read fail: value parse error: %v (%d)", err, i)
+				if perr == nil {
+					perr = fmt.Errorf("value parse error (column %d): %v", i+1, err)
+				}
 			}
 			return v
 		}
 
+		var kg float64
 		ep := func() float64 {
 			const (
 				a = 0.75
@@ -75,6 +211,7 @@ func ReadOWRC(csvfp string, cakm2, latitude float64) ([]time.Time, []Dset) {
 				fmt.Printf(" tx<tn %.1f !< %.1f\n", tx, tn)
 				tx, tn = tn, tx
 			}
+			kg = si.GlobalFromPotential(tx, tn, a, b, c, doy)
 			return func(Kg float64) float64 {
 				const (
 					alpha = 0.61
@@ -82,15 +219,17 @@ func ReadOWRC(csvfp string, cakm2, latitude float64) ([]time.Time, []Dset) {
 				)
 				tm := (tx + tn) / 2.
 				return pet.Makkink(Kg, tm, pa, alpha, beta)
-			}(si.GlobalFromPotential(tx, tn, a, b, c, doy)) * 1000. // mm/d
+			}(kg) * 1000. * df // mm/ts
 		}()
+		if perr != nil {
+			return fail(ln, perr)
+		}
 		if ep < 0 {
-			panic("ep less than 0")
+			return fail(ln, fmt.Errorf("negative PET computed (%f)", ep))
 		}
 
-		ts = append(ts, t)
-		o = append(o, Dset{ // "Date","Flow","Flag","Tx","Tn","Rf","Sf","Sm","Pa"
-			Q:  g(1) * cms2mmd,
+		d := Dset{ // "Date","Flow","Flag","Tx","Tn","Rf","Sf","Sm","Pa"
+			Q:  g(1) * cms2mmts,
 			Tx: g(3),
 			Tn: g(4),
 			rf: g(5),
@@ -98,8 +237,14 @@ func ReadOWRC(csvfp string, cakm2, latitude float64) ([]time.Time, []Dset) {
 			sm: g(7),
 			// pa: g(8),
 			Ep: ep,
-		})
+			Kg: kg,
+		}
+		if perr != nil {
+			return fail(ln, perr)
+		}
+		dt = append(dt, t)
+		o = append(o, d)
 	}
 
-	return ts, o
+	return dt, o, nil
 }
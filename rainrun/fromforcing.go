@@ -0,0 +1,39 @@
+package rainrun
+
+import (
+	"fmt"
+
+	"github.com/maseology/goHydro/forcing"
+)
+
+// FromForcing reads location ID lid of a forcing.Forcing: precipitation,
+// temperature (required) and, when given, potential evaporation. As with the
+// other loaders, depths are multiplied by Loader.Depth and absent PET is
+// computed; the timestep is taken from frc. Observed flows are missing.
+func (ld *Loader) FromForcing(frc *forcing.Forcing, lid int) (*Frc, error) {
+	i, ok := frc.Index(lid)
+	if !ok {
+		return nil, fmt.Errorf("rainrun.FromForcing: location %d not found", lid)
+	}
+	if frc.Ta == nil {
+		return nil, fmt.Errorf("rainrun.FromForcing: temperature required")
+	}
+	ld = ld.local()
+	ld.Timestep = frc.IntervalSec
+
+	var has [ncol]bool
+	has[ColPrecip], has[ColTm], has[ColPET] = true, true, frc.Ea != nil
+	rows := make([]row, frc.Nstep())
+	for j := range rows {
+		rows[j][ColPrecip] = frc.Ya[i][j]
+		rows[j][ColTm] = frc.Ta[i][j]
+		if has[ColPET] {
+			rows[j][ColPET] = frc.Ea[i][j]
+		}
+	}
+	f, err := ld.build(frc.T, rows, has)
+	if err != nil {
+		return nil, fmt.Errorf("rainrun.FromForcing: %v", err)
+	}
+	return f, nil
+}
@@ -1,7 +1,6 @@
 package rainrun
 
 import (
-	"log"
 	"math"
 
 	"github.com/maseology/glbopt"
@@ -13,35 +12,28 @@ import (
 type GR4J struct {
 	prd, rte           res
 	uh1, uh2, cv1, cv2 []float64
-	x2, qsplt          float64
+	x2, qsplt, pbeta   float64
 }
 
 // New GR4J constructor
+// [x1, x2, x3, x4, (optional) initial runoff q0]
 func (m *GR4J) New(p ...float64) {
 	if p[3] < .5 { //|| p[4] <= 0. || p[4] >= 1. {
-		log.Fatalln("GR4J input error")
+		panic("GR4J input error")
 	}
 
 	m.prd.new(p[0], 0.) // prd: x1: maximum capacity of the "production (SMA) store"
 	m.x2 = p[1]         // x2: water exchange coefficient (>0 for water imports, <0 for exports, =0 for no exchange)
 	m.rte.new(p[2], 0.) // rte: x3: reference capacity of "routing store"
 	x4 := p[3]          // x4: unit hydrograph time parameter
+	m.pbeta = 9. / 4.   // percolation constant of daily timesteps
 	// m.qsplt = p[4]      // qsplt: unitHydrographPartition, fixed in paper to = 0.9
 
-	m.rte.sto = func() float64 {
-		q0 := p[len(p)-1] // frc.Dat[0][2]
-		smpl := func(u float64) float64 {
-			return mmaths.LinearTransform(0., 10., u)
-		}
-		opt := func(u float64) float64 {
-			x3i := smpl(u)
-			qr := p[1] * math.Pow(x3i/p[2], 7./2.)                       // eq.18 catchment GW exchange; x2: water exchange coefficient (>0 for water imports, <0 for exports, =0 for no exchange)
-			qr += x3i * (1. - math.Pow(1.+math.Pow(x3i/p[2], 4.), -.25)) // eq.20
-			return math.Abs(qr-q0) / q0
-		}
-		u, _ := glbopt.Fibonacci(opt)
-		return smpl(u)
-	}()
+	if len(p) < 5 {
+		m.rte.sto = p[2] / 2. // no initial runoff given: routing store half full
+	} else {
+		m.rte.sto = initialRouting(p[1], p[2], p[4])
+	}
 
 	// unit hydrographs build
 	func() { // build UH1
@@ -90,7 +82,28 @@ func (m *GR4J) New(p ...float64) {
 	}()
 }
 
-// Update state for daily inputs
+// initialRouting returns the routing store in equilibrium with runoff q0
+func initialRouting(x2, x3, q0 float64) float64 {
+	smpl := func(u float64) float64 {
+		return mmaths.LinearTransform(0., 10., u)
+	}
+	opt := func(u float64) float64 {
+		x3i := smpl(u)
+		qr := x2 * math.Pow(x3i/x3, 7./2.)                         // eq.18 catchment GW exchange; x2: water exchange coefficient (>0 for water imports, <0 for exports, =0 for no exchange)
+		qr += x3i * (1. - math.Pow(1.+math.Pow(x3i/x3, 4.), -.25)) // eq.20
+		return math.Abs(qr-q0) / q0
+	}
+	u, _ := glbopt.Fibonacci(opt)
+	return smpl(u)
+}
+
+// SetTimestep adjusts the percolation constant to timestep ts [s], such that
+// percolation remains proportional to the timestep (9/4 daily, ≈21/4 hourly; GR4H, Mathevet, 2005)
+func (m *GR4J) SetTimestep(ts float64) {
+	m.pbeta = 9. / 4. * math.Pow(86400./ts, .25)
+}
+
+// Update state
 func (m *GR4J) Update(p, ep float64) (float64, float64, float64) {
 	var pn, en, es float64
 	if p >= ep {
@@ -112,8 +125,8 @@ func (m *GR4J) Update(p, ep float64) (float64, float64, float64) {
 		panic("GR4J error: production store error")
 	}
 
-	g := m.prd.sto * (1. - math.Pow(1.+math.Pow(4.*m.prd.storageFraction()/9., 4.), -0.25)) // eq.6 "Perc": percolation from production zone
-	if m.prd.update(-g) < 0. {                                                              // eq.7 this line must be left here such that prd is updated
+	g := m.prd.sto * (1. - math.Pow(1.+math.Pow(m.prd.storageFraction()/m.pbeta, 4.), -0.25)) // eq.6 "Perc": percolation from production zone
+	if m.prd.update(-g) < 0. {                                                                // eq.7 this line must be left here such that prd is updated
 		panic("GR4J error: percolation")
 	}
 
@@ -160,3 +173,28 @@ func (m *GR4J) updateUH2(pr float64) float64 {
 func (m *GR4J) Storage() float64 {
 	return m.prd.sto + m.rte.sto
 }
+
+// State returns the model state: [prd, rte, cv1..., cv2...]
+func (m *GR4J) State() []float64 {
+	x := make([]float64, 0, 2+len(m.cv1)+len(m.cv2))
+	x = append(x, m.prd.sto, m.rte.sto)
+	x = append(x, m.cv1...)
+	return append(x, m.cv2...)
+}
+
+// SetState restores the model state returned by State()
+func (m *GR4J) SetState(x []float64) error {
+	if err := checkState("GR4J", x, 2+len(m.cv1)+len(m.cv2)); err != nil {
+		return err
+	}
+	m.prd.sto, m.rte.sto = x[0], x[1]
+	copy(m.cv1, x[2:])
+	copy(m.cv2, x[2+len(m.cv1):])
+	return nil
+}
+
+// StateBounds returns the bounds of the state variables returned by State()
+func (m *GR4J) StateBounds() (lo, hi []float64) {
+	lo, hi = storages(m.prd.cap, m.rte.cap)
+	return fixed(lo, hi, len(m.cv1)+len(m.cv2)) // unit hydrograph ordinates
+}
@@ -42,3 +42,20 @@ func (m *MakkinkCCFGR4J) Update(d *Dset) (y, a, r, g float64) {
 	a, r, g = m.GR4J.Update(y, d.Ep)
 	return
 }
+
+// State returns the model state: [GR4J state..., snowpack state...]
+func (m *MakkinkCCFGR4J) State() []float64 {
+	return append(m.GR4J.State(), m.SP.State()...)
+}
+
+// SetState restores the model state returned by State()
+func (m *MakkinkCCFGR4J) SetState(x []float64) error {
+	nsp := len(m.SP.State())
+	if len(x) < nsp {
+		return checkState("MakkinkCCFGR4J", x, nsp+len(m.GR4J.State()))
+	}
+	if err := m.GR4J.SetState(x[:len(x)-nsp]); err != nil {
+		return err
+	}
+	return m.SP.SetState(x[len(x)-nsp:])
+}
@@ -145,3 +145,26 @@ func (m *HBV) Storage() float64 {
 
 // // Ndim returns the number of dimensions
 // func (m *HBV) Ndim() int { return 10 }
+
+// State returns the model state: [sm, suz, slz, maxbas...]
+func (m *HBV) State() []float64 {
+	x := make([]float64, 0, 3+len(m.maxbas.SQ))
+	x = append(x, m.sm, m.suz, m.slz)
+	return append(x, m.maxbas.SQ...)
+}
+
+// SetState restores the model state returned by State()
+func (m *HBV) SetState(x []float64) error {
+	if err := checkState("HBV", x, 3+len(m.maxbas.SQ)); err != nil {
+		return err
+	}
+	m.sm, m.suz, m.slz = x[0], x[1], x[2]
+	copy(m.maxbas.SQ, x[3:])
+	return nil
+}
+
+// StateBounds returns the bounds of the state variables returned by State()
+func (m *HBV) StateBounds() (lo, hi []float64) {
+	lo, hi = storages(m.fc, unbounded, unbounded)
+	return fixed(lo, hi, len(m.maxbas.SQ)) // routing ordinates
+}
@@ -51,3 +51,20 @@ func (m *CCFHBV) Update(v *Dset) (y, a, r, g float64) {
 	// r = y
 	return
 }
+
+// State returns the model state: [HBV state..., snowpack state...]
+func (m *CCFHBV) State() []float64 {
+	return append(m.HBV.State(), m.SP.State()...)
+}
+
+// SetState restores the model state returned by State()
+func (m *CCFHBV) SetState(x []float64) error {
+	nsp := len(m.SP.State())
+	if len(x) < nsp {
+		return checkState("CCFHBV", x, nsp+len(m.HBV.State()))
+	}
+	if err := m.HBV.SetState(x[:len(x)-nsp]); err != nil {
+		return err
+	}
+	return m.SP.SetState(x[len(x)-nsp:])
+}
@@ -10,6 +10,7 @@ type HMETS struct {
 	lv, lp                       *res
 	gsr, gdr                     *convolution.Convolution
 	eteff, fimp, cr, cv, cvp, cp float64
+	ga                           [4]float64 // gamma shape and rate of surface and delayed runoff [days]
 }
 
 // New HMETS constructor
@@ -25,8 +26,7 @@ func (m *HMETS) New(p ...float64) {
 		m.cv = .1    // Fraction of the water for hypodermic flow
 		m.cvp = .2   // Fraction of the water for groundwater recharge
 		m.cp = .1    // Fraction of the water for groundwater flow
-		m.gsr = convolution.NewGammaConvolution(.1, .1, 86400.)
-		m.gdr = convolution.NewGammaConvolution(.1, .1, 86400.)
+		m.ga = [4]float64{.1, .1, .1, .1}
 		m.lv = &res{cap: 300.}                                  // Maximum level of the vadose zone [mm]
 		m.lp = &res{sto: min(gw0/365.24/m.cp, 500.), cap: 500.} // max basin phreatic zone capacity [mm]
 	} else {
@@ -38,15 +38,21 @@ func (m *HMETS) New(p ...float64) {
 		m.cv = p[5]                                             // Fraction of the water for hypodermic flow
 		m.cvp = p[6]                                            // Fraction of the water for groundwater recharge
 		m.cp = p[7]                                             // Fraction of the water for groundwater flow
-		m.gsr = convolution.NewGammaConvolution(p[8], p[9], 86400.)
-		m.gdr = convolution.NewGammaConvolution(p[10], p[11], 86400.)
+		m.ga = [4]float64{p[8], p[9], p[10], p[11]}
 	}
+	m.SetTimestep(86400.)
 	if fracCheck(m.cv+m.cvp) || fracCheck(m.cr) {
 		panic("HMETS input error")
 	}
 }
 
-// Update state for daily inputs
+// SetTimestep builds the surface and delayed runoff unit hydrographs at timestep ts [s]
+func (m *HMETS) SetTimestep(ts float64) {
+	m.gsr = convolution.NewGammaConvolution(m.ga[0], m.ga[1], ts)
+	m.gdr = convolution.NewGammaConvolution(m.ga[2], m.ga[3], ts)
+}
+
+// Update state
 func (m *HMETS) Update(pn, ep float64) (float64, float64, float64) {
 	a := m.eteff * ep
 
@@ -89,3 +95,29 @@ func (m *HMETS) Update(pn, ep float64) (float64, float64, float64) {
 func (m *HMETS) Storage() float64 {
 	return m.lv.sto + m.lp.sto
 }
+
+// State returns the model state: [lv, lp, gsr..., gdr...]
+func (m *HMETS) State() []float64 {
+	x := []float64{m.lv.sto, m.lp.sto}
+	x = append(x, m.gsr.State()...)
+	return append(x, m.gdr.State()...)
+}
+
+// SetState restores the model state returned by State()
+func (m *HMETS) SetState(x []float64) error {
+	ngsr := len(m.gsr.State())
+	if err := checkState("HMETS", x, 2+ngsr+len(m.gdr.State())); err != nil {
+		return err
+	}
+	m.lv.sto, m.lp.sto = x[0], x[1]
+	if err := m.gsr.SetState(x[2 : 2+ngsr]); err != nil {
+		return err
+	}
+	return m.gdr.SetState(x[2+ngsr:])
+}
+
+// StateBounds returns the bounds of the state variables returned by State()
+func (m *HMETS) StateBounds() (lo, hi []float64) {
+	lo, hi = storages(m.lv.cap, m.lp.cap)
+	return fixed(lo, hi, len(m.gsr.State())+len(m.gdr.State())) // convolution ordinates
+}
@@ -0,0 +1,77 @@
+package rainrun
+
+import "math"
+
+// HYMOD model
+// ref: Boyle, D.P., 2001. Multicriteria calibration of hydrologic models. Ph.D. Dissertation. Department of Hydrology and Water Resources, University of Arizona, Tucson.
+// also see: Wagener T., D.P. Boyle, M.J. Lees, H.S. Wheater, H.V. Gupta, S. Sorooshian, 2001. A framework for development and application of hydrological models. Hydrology and Earth System Sciences 5(1). pp. 13-26.
+type HYMOD struct {
+	quick          [3]res
+	slow           res
+	cmax, b, alpha float64
+	sto            float64 // soil moisture storage
+}
+
+// New HYMOD constructor
+// [cmax, bexp, alpha, kquick, kslow]
+func (m *HYMOD) New(p ...float64) {
+	if p[0] <= 0. || p[1] < 0. || fracCheck(p[2]) || fracCheck(p[3]) || fracCheck(p[4]) {
+		panic("HYMOD input error")
+	}
+	m.cmax = p[0]  // maximum storage capacity within the catchment
+	m.b = p[1]     // degree of spatial variability of storage capacities
+	m.alpha = p[2] // fraction of excess routed through the quick-flow reservoirs
+	for i := range m.quick {
+		m.quick[i].new(0., p[3]) // three identical quick-flow reservoirs in series
+	}
+	m.slow.new(0., p[4]) // slow-flow reservoir
+}
+
+// Update state for daily inputs
+func (m *HYMOD) Update(p, ep float64) (float64, float64, float64) {
+	smax := m.cmax / (1. + m.b) // maximum catchment storage
+
+	// critical storage capacity of the Pareto-distributed storage elements
+	ct := m.cmax * (1. - math.Pow(1.-math.Min(1., m.sto/smax), 1./(m.b+1.)))
+	er1 := math.Max(p-m.cmax+ct, 0.) // excess from elements filled beyond cmax
+	ct = math.Min(ct+p, m.cmax)
+	sn := smax * (1. - math.Pow(1.-ct/m.cmax, m.b+1.))
+	er2 := math.Max(p-er1-(sn-m.sto), 0.) // excess from the partially-filled elements
+
+	a := math.Min(sn, ep*sn/smax) // evaporation proportional to relative storage
+	m.sto = sn - a
+
+	er := er1 + er2
+	qi := m.alpha * er
+	for i := range m.quick {
+		m.quick[i].update(qi)
+		qi = m.quick[i].decayExp()
+	}
+	g := (1. - m.alpha) * er
+	m.slow.update(g)
+	return a, qi + m.slow.decayExp(), g
+}
+
+// Storage returns total storage
+func (m *HYMOD) Storage() float64 {
+	return m.sto + m.quick[0].sto + m.quick[1].sto + m.quick[2].sto + m.slow.sto
+}
+
+// State returns the model state: [soil, quick1, quick2, quick3, slow]
+func (m *HYMOD) State() []float64 {
+	return []float64{m.sto, m.quick[0].sto, m.quick[1].sto, m.quick[2].sto, m.slow.sto}
+}
+
+// SetState restores the model state returned by State()
+func (m *HYMOD) SetState(x []float64) error {
+	if err := checkState("HYMOD", x, 5); err != nil {
+		return err
+	}
+	m.sto, m.quick[0].sto, m.quick[1].sto, m.quick[2].sto, m.slow.sto = x[0], x[1], x[2], x[3], x[4]
+	return nil
+}
+
+// StateBounds returns the bounds of the state variables returned by State()
+func (m *HYMOD) StateBounds() (lo, hi []float64) {
+	return storages(m.cmax, unbounded, unbounded, unbounded, unbounded)
+}
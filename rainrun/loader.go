@@ -0,0 +1,272 @@
+package rainrun
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maseology/goHydro/solirrad"
+)
+
+// Column identifies a forcing variable read by a Loader
+type Column int
+
+const (
+	ColDate   Column = iota // date, parsed using Loader.Layout
+	ColFlow                 // streamflow [m³/s], converted to mm/ts when Loader.Cakm2 > 0, otherwise [mm/ts]
+	ColTx                   // maximum temperature [°C]
+	ColTn                   // minimum temperature [°C]
+	ColTm                   // mean temperature [°C], used when ColTx/ColTn are absent
+	ColPrecip               // total precipitation [mm/ts], partitioned into rain and snow about Loader.Tcrit
+	ColRain                 // rainfall [mm/ts]
+	ColSnow                 // snowfall [mm/ts]
+	ColMelt                 // snowmelt [mm/ts]
+	ColPa                   // atmospheric pressure [kPa]
+	ColPET                  // potential evaporation [mm/ts]
+	ColKg                   // global shortwave radiation [MJ/m²/d]
+	ncol
+)
+
+// row holds the values of a single timestep, indexed by Column
+type row [ncol]float64
+
+// Loader reads forcing data into a Frc. Columns not mapped are absent: absent
+// precipitation components are taken as zero, absent radiation is estimated
+// from the temperature range and latitude, and absent PET is computed using a
+// registered PET estimator. No-data values are read as NaN (see Frc.FillGaps).
+// The location is returned in Frc.Latitude, Frc.Cakm2 and Frc.Elevation.
+type Loader struct {
+	Columns   map[Column]int // zero-based csv column index of each variable read
+	Layout    string         // date layout (default "2006-01-02")
+	Comma     rune           // field delimiter (default ',')
+	Skip      int            // number of header lines
+	Missing   []string       // no-data values (default "", "NA", "NaN", "-999", "-9999")
+	Timestep  float64        // [s] (default 86400)
+	Depth     float64        // factor converting depths to mm (default 1)
+	Cakm2     float64        // catchment area [km²], flows are converted from m³/s when > 0
+	Latitude  float64        // [°]
+	Elevation float64        // [m]
+	Tcrit     float64        // critical temperature partitioning precipitation into rain and snow [°C]
+	PET       string         // registered PET estimator used when ColPET is absent (default "Makkink", see PETs())
+	PETParams []float64      // PET estimator parameters (default: registered defaults)
+}
+
+// OWRC returns a Loader of the "Date","Flow","Flag","Tx","Tn","Rf","Sf","Sm","Pa" csv layout read by ReadOWRC()
+func OWRC(cakm2, latitude float64) *Loader {
+	return &Loader{
+		Columns:  map[Column]int{ColDate: 0, ColFlow: 1, ColTx: 3, ColTn: 4, ColRain: 5, ColSnow: 6, ColMelt: 7, ColPa: 8},
+		Skip:     1,
+		Cakm2:    cakm2,
+		Latitude: latitude,
+	}
+}
+
+// local returns a copy of the Loader with defaults set, such that values read
+// from a file (e.g., timestep, catchment properties) are not kept by the caller's Loader
+func (ld *Loader) local() *Loader {
+	l := *ld
+	l.defaults()
+	return &l
+}
+
+func (ld *Loader) defaults() {
+	if ld.Layout == "" {
+		ld.Layout = "2006-01-02"
+	}
+	if ld.Comma == 0 {
+		ld.Comma = ','
+	}
+	if ld.Missing == nil {
+		ld.Missing = []string{"", "NA", "NaN", "-999", "-9999"}
+	}
+	if ld.Timestep <= 0. {
+		ld.Timestep = 86400.
+	}
+	if ld.Depth <= 0. {
+		ld.Depth = 1.
+	}
+	if ld.PET == "" {
+		ld.PET = "Makkink"
+	}
+}
+
+// Load reads a csv file
+func (ld *Loader) Load(fp string) (*Frc, error) {
+	f, err := os.Open(fp)
+	if err != nil {
+		return nil, fmt.Errorf("rainrun.Loader.Load: %v", err)
+	}
+	defer f.Close()
+	frc, err := ld.Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("rainrun.Loader.Load %s: %v", fp, err)
+	}
+	frc.FilePath = fp
+	return frc, nil
+}
+
+// Read reads csv-formatted forcing data
+func (ld *Loader) Read(r io.Reader) (*Frc, error) {
+	ld = ld.local()
+	idt, ok := ld.Columns[ColDate]
+	if !ok {
+		return nil, fmt.Errorf("date column (ColDate) not mapped")
+	}
+	for c, i := range ld.Columns {
+		if c < 0 || c >= ncol {
+			return nil, fmt.Errorf("unrecognized column %d", c)
+		}
+		if i < 0 {
+			return nil, fmt.Errorf("column %d mapped to negative index %d", c, i)
+		}
+	}
+	has := ld.mapped()
+
+	cr := csv.NewReader(r)
+	cr.Comma = ld.Comma
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	dt, rows := []time.Time{}, []row{}
+	for ln := 1; ; ln++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if ln <= ld.Skip {
+			continue
+		}
+		if idt >= len(rec) {
+			return nil, fmt.Errorf("line %d: date column %d out of range", ln, idt)
+		}
+		t, err := time.Parse(ld.Layout, rec[idt])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", ln, err)
+		}
+		var v row
+		for c, i := range ld.Columns {
+			if c == ColDate {
+				continue
+			}
+			if i >= len(rec) {
+				return nil, fmt.Errorf("line %d: column %d out of range", ln, i)
+			}
+			if v[c], err = ld.parse(rec[i]); err != nil {
+				return nil, fmt.Errorf("line %d, column %d: %v", ln, i, err)
+			}
+		}
+		if ld.Cakm2 > 0. {
+			v[ColFlow] *= ld.Timestep / 1000. / ld.Cakm2 // m³/s to mm/ts
+		} else {
+			v[ColFlow] *= ld.Depth
+		}
+		dt = append(dt, t)
+		rows = append(rows, v)
+	}
+	return ld.build(dt, rows, has)
+}
+
+func (ld *Loader) mapped() [ncol]bool {
+	var has [ncol]bool
+	for c := range ld.Columns {
+		has[c] = true
+	}
+	return has
+}
+
+func (ld *Loader) parse(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	for _, m := range ld.Missing {
+		if s == m {
+			return math.NaN(), nil
+		}
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// build converts rows of timestep values into a Frc, estimating absent variables
+func (ld *Loader) build(dt []time.Time, rows []row, has [ncol]bool) (*Frc, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("no data read")
+	}
+	var et PET
+	if !has[ColPET] {
+		pi, ok := pets[ld.PET]
+		if !ok {
+			return nil, fmt.Errorf("unrecognized PET estimator: %s", ld.PET)
+		}
+		p := ld.PETParams
+		if p == nil {
+			for _, pp := range pi.Params {
+				p = append(p, pp.Default)
+			}
+		} else if len(p) != len(pi.Params) {
+			return nil, fmt.Errorf("%s PET: %d parameters given, %d expected", ld.PET, len(p), len(pi.Params))
+		}
+		et = pi.New()
+		et.New(p...)
+	}
+
+	const ( // radiation estimated from the temperature range (see ReadOWRC)
+		a = 0.75
+		b = 0.0025
+		c = 2.5
+	)
+	si := solirrad.New(ld.Latitude, 0., 0.)
+	df := ld.Timestep / 86400.
+	o := make([]Dset, len(rows))
+	for i, v := range rows {
+		d := &o[i]
+		d.Q = v[ColFlow]
+		if !has[ColFlow] {
+			d.Q = math.NaN()
+		}
+		d.Tx, d.Tn = v[ColTx], v[ColTn]
+		if !has[ColTx] || !has[ColTn] {
+			d.Tx, d.Tn = v[ColTm], v[ColTm]
+			if !has[ColTm] {
+				return nil, fmt.Errorf("temperature columns required (ColTx and ColTn, or ColTm)")
+			}
+		}
+		if d.Tx < d.Tn {
+			d.Tx, d.Tn = d.Tn, d.Tx
+		}
+		d.rf, d.sf, d.sm = v[ColRain]*ld.Depth, v[ColSnow]*ld.Depth, v[ColMelt]*ld.Depth
+		if has[ColPrecip] && !has[ColRain] && !has[ColSnow] {
+			if p, tm := v[ColPrecip]*ld.Depth, (d.Tx+d.Tn)/2.; math.IsNaN(tm) || tm > ld.Tcrit {
+				d.rf = p
+			} else {
+				d.sf = p
+			}
+		}
+		d.pa = v[ColPa]
+		if !has[ColPa] {
+			d.pa = pres / 1000. // kPa
+		}
+		d.Kg = v[ColKg]
+		if !has[ColKg] {
+			d.Kg = si.GlobalFromPotential(d.Tx, d.Tn, a, b, c, dt[i].YearDay())
+		}
+		if et != nil {
+			d.Ep = et.Evaporation(d) * df // mm/d to mm/ts
+		} else {
+			d.Ep = v[ColPET] * ld.Depth
+		}
+	}
+	return &Frc{
+		D:         o,
+		DT:        dt,
+		Latitude:  ld.Latitude,
+		Cakm2:     ld.Cakm2,
+		Elevation: ld.Elevation,
+		Timestep:  ld.Timestep,
+		Ndt:       len(o),
+	}, nil
+}
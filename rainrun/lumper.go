@@ -5,4 +5,5 @@ type Lumper interface {
 	New(p ...float64)
 	Update(p, ep float64) (float64, float64, float64)
 	Storage() float64
+	Stater
 }
@@ -150,3 +150,22 @@ func (m *ManabeGW) Storage() float64 {
 
 // // Ndim returns the number of dimensions
 // func (m *ManabeGW) Ndim() int { return 5 }
+
+// State returns the model state: [r, gwsto]
+func (m *ManabeGW) State() []float64 {
+	return []float64{m.r.sto, m.gwsto}
+}
+
+// SetState restores the model state returned by State()
+func (m *ManabeGW) SetState(x []float64) error {
+	if err := checkState("ManabeGW", x, 2); err != nil {
+		return err
+	}
+	m.r.sto, m.gwsto = x[0], x[1]
+	return nil
+}
+
+// StateBounds returns the bounds of the state variables returned by State()
+func (m *ManabeGW) StateBounds() (lo, hi []float64) {
+	return storages(m.r.cap, unbounded)
+}
@@ -0,0 +1,69 @@
+package rainrun
+
+import (
+	"fmt"
+	"math"
+)
+
+// Mask flags the timesteps of an observed series holding data (true), as
+// opposed to gaps (false): NaN, infinite or negative (no-data) values
+type Mask []bool
+
+// NewMask returns the mask of observed series o
+func NewMask(o []float64) Mask {
+	m := make(Mask, len(o))
+	for i, v := range o {
+		m[i] = observed(v)
+	}
+	return m
+}
+
+// Mask returns the mask of observed flows (Dset.Q)
+func (f *Frc) Mask() Mask {
+	m := make(Mask, len(f.D))
+	for i, d := range f.D {
+		m[i] = observed(d.Q)
+	}
+	return m
+}
+
+func observed(v float64) bool { return !math.IsNaN(v) && !math.IsInf(v, 0) && v >= 0. }
+
+// Count returns the number of observed timesteps
+func (m Mask) Count() int {
+	n := 0
+	for _, b := range m {
+		if b {
+			n++
+		}
+	}
+	return n
+}
+
+// Coverage returns the fraction of timesteps observed
+func (m Mask) Coverage() float64 {
+	if len(m) == 0 {
+		return 0.
+	}
+	return float64(m.Count()) / float64(len(m))
+}
+
+// Apply returns the observed and simulated values of the observed timesteps
+func (m Mask) Apply(o, s []float64) (oo, ss []float64) {
+	oo, ss = make([]float64, 0, len(m)), make([]float64, 0, len(m))
+	for i, b := range m {
+		if b {
+			oo = append(oo, o[i])
+			ss = append(ss, s[i])
+		}
+	}
+	return
+}
+
+// String reports the data coverage
+func (m Mask) String() string {
+	return fmt.Sprintf("%.1f%% coverage (%d of %d timesteps observed)", 100.*m.Coverage(), m.Count(), len(m))
+}
+
+// Masked removes the gaps of observed series o, along with the corresponding simulated values s
+func Masked(o, s []float64) ([]float64, []float64) { return NewMask(o).Apply(o, s) }
@@ -0,0 +1,72 @@
+package rainrun
+
+import (
+	"fmt"
+
+	"github.com/maseology/goHydro/met"
+)
+
+// .met water-balance data types read into a Frc
+var metColumns = map[string]Column{
+	"MaxDailyT":           ColTx,
+	"MinDailyT":           ColTn,
+	"Temperature":         ColTm,
+	"Precipitation":       ColPrecip,
+	"Rainfall":            ColRain,
+	"Snowfall":            ColSnow,
+	"SnowMelt":            ColMelt,
+	"AtmosphericYield":    ColRain, // rainfall + snowmelt
+	"AtmosphericPressure": ColPa,
+	"AtmosphericDemand":   ColPET,
+	"UnitDischarge":       ColFlow,
+}
+
+// LoadMET reads location (zero-based index) loc of a .met file. Depths
+// (precipitation, yield, demand and unit discharge) are multiplied by
+// Loader.Depth (i.e., set to 1000. when stored in metres); the timestep is
+// taken from the file.
+func (ld *Loader) LoadMET(fp string, loc int) (*Frc, error) {
+	h, c, err := met.ReadMET(fp, false)
+	if err != nil {
+		return nil, fmt.Errorf("rainrun.LoadMET: %v", err)
+	}
+	if loc < 0 || loc >= h.Nloc() {
+		return nil, fmt.Errorf("rainrun.LoadMET %s: location %d out of range (%d locations)", fp, loc, h.Nloc())
+	}
+	if h.IntervalSec() <= 0. {
+		return nil, fmt.Errorf("rainrun.LoadMET %s: a fixed timestep interval is required", fp)
+	}
+	ld = ld.local()
+	ld.Timestep = h.IntervalSec()
+
+	var has [ncol]bool
+	xr := h.WBDCxr()
+	for s, col := range metColumns {
+		if _, ok := xr[s]; ok {
+			if col == ColRain && has[ColRain] {
+				return nil, fmt.Errorf("rainrun.LoadMET %s: both Rainfall and AtmosphericYield given", fp)
+			}
+			has[col] = true
+		}
+	}
+
+	rows := make([]row, len(c.T))
+	for i := range c.T {
+		for s, j := range xr {
+			col, ok := metColumns[s]
+			if !ok {
+				continue
+			}
+			rows[i][col] = c.D[i][loc][j]
+			if col == ColFlow {
+				rows[i][col] *= ld.Depth // other depths are converted in build()
+			}
+		}
+	}
+	frc, err := ld.build(c.T, rows, has)
+	if err != nil {
+		return nil, fmt.Errorf("rainrun.LoadMET %s: %v", fp, err)
+	}
+	frc.FilePath = fp
+	return frc, nil
+}
@@ -0,0 +1,141 @@
+package rainrun
+
+// Model : interface to rainfall-runoff models driven by a complete forcing timestep
+type Model interface {
+	New(p ...float64)
+	Update(d *Dset) (y, a, r, g float64) // yield, aet, runoff, recharge
+	Storage() float64
+	Stater
+}
+
+// Coupled pairs a Lumper with an optional snowpack model and PET estimator
+type Coupled struct {
+	L   Lumper
+	SP  Snow   // when nil, yield is taken from the forcing data (Dset.Yield)
+	ET  PET    // when nil, PET is taken from the forcing data (Dset.Ep)
+	nm  string // registered model name, see Name()
+	n   []int
+	ts  float64 // timestep [s]; 0 for daily
+	err error   // first snowpack error, see Err()
+}
+
+// New Coupled constructor
+// [Lumper parameters..., snowpack parameters..., PET parameters...]
+// the parameter count of each component is set when built from the registry,
+// otherwise all parameters are passed to the Lumper
+func (c *Coupled) New(p ...float64) {
+	if len(c.n) != 3 {
+		c.L.New(p...)
+	} else {
+		c.L.New(p[:c.n[0]]...)
+		if c.SP != nil {
+			c.SP.New(p[c.n[0] : c.n[0]+c.n[1]]...)
+		}
+		if c.ET != nil {
+			c.ET.New(p[c.n[0]+c.n[1]:]...)
+		}
+	}
+	if c.ts > 0. {
+		c.SetTimestep(c.ts)
+	}
+	c.err = nil
+}
+
+// SetTimestep sets the timestep [s] of the Lumper and snowpack model, and
+// scales the (daily) PET estimate accordingly; must be called following New()
+func (c *Coupled) SetTimestep(ts float64) {
+	c.ts = ts
+	if t, ok := c.L.(Timestepper); ok {
+		t.SetTimestep(ts)
+	}
+	if t, ok := c.SP.(Timestepper); ok {
+		t.SetTimestep(ts)
+	}
+}
+
+// Update state
+func (c *Coupled) Update(d *Dset) (y, a, r, g float64) {
+	if c.SP == nil {
+		y = d.Yield()
+	} else {
+		var err error
+		if y, err = c.SP.Update(d); err != nil {
+			if c.err == nil {
+				c.err = err
+			}
+			y = d.Precip() // snowpack bypassed
+		}
+	}
+	ep := d.Ep
+	if c.ET != nil {
+		ep = c.ET.Evaporation(d)
+		if c.ts > 0. {
+			ep *= c.ts / 86400.
+		}
+	}
+	a, r, g = c.L.Update(y, ep)
+	return
+}
+
+// Name returns the registered model name (e.g., "GR4J+CCF+Makkink") when built
+// from the registry, otherwise the type name of the Lumper
+func (c *Coupled) Name() string {
+	if c.nm != "" {
+		return c.nm
+	}
+	return typeName(c.L)
+}
+
+// Err returns the first error raised by the snowpack model (e.g., forcing out
+// of range), for which the timestep's precipitation was passed through as yield
+func (c *Coupled) Err() error { return c.err }
+
+// Storage returns the total model storage, including snowpack
+func (c *Coupled) Storage() float64 {
+	if c.SP == nil {
+		return c.L.Storage()
+	}
+	return c.L.Storage() + c.SP.Storage()
+}
+
+// State returns the model state: [Lumper state..., snowpack state...]
+func (c *Coupled) State() []float64 {
+	if c.SP == nil {
+		return c.L.State()
+	}
+	return append(c.L.State(), c.SP.State()...)
+}
+
+// StateBounds returns the bounds of the state variables returned by State();
+// nil when the Lumper or snowpack model does not report its bounds
+func (c *Coupled) StateBounds() (lo, hi []float64) {
+	b, ok := c.L.(Bounder)
+	if !ok {
+		return nil, nil
+	}
+	lo, hi = b.StateBounds()
+	if c.SP == nil {
+		return
+	}
+	bs, ok := c.SP.(Bounder)
+	if !ok {
+		return nil, nil
+	}
+	los, his := bs.StateBounds()
+	return append(lo, los...), append(hi, his...)
+}
+
+// SetState restores the model state returned by State()
+func (c *Coupled) SetState(x []float64) error {
+	if c.SP == nil {
+		return c.L.SetState(x)
+	}
+	nsp := len(c.SP.State())
+	if len(x) < nsp {
+		return checkState("Coupled", x, nsp+len(c.L.State()))
+	}
+	if err := c.L.SetState(x[:len(x)-nsp]); err != nil {
+		return err
+	}
+	return c.SP.SetState(x[len(x)-nsp:])
+}
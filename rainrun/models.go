@@ -0,0 +1,237 @@
+package rainrun
+
+// builtin set of registered models
+// parameter order follows that of each model's New() constructor; bounds mirror those found in rainrun/sample
+// timestep-dependent parameters are given at a daily timestep (see Param.Step)
+var builtin = []ModelInfo{
+	{
+		Name: "Atkinson",
+		New:  func() Lumper { return &Atkinson{} },
+		Params: []Param{
+			{Name: "sbc", Unit: "mm", Desc: "bucket capacity", Lower: 0., Upper: 500., Default: 250.},
+			{Name: "sfc", Unit: "mm", Desc: "threshold storage", Lower: 0., Upper: 200., Default: 100.},
+			{Name: "coverdense", Unit: "-", Desc: "fractional forest cover", Lower: 0., Upper: 1., Default: .5},
+			{Name: "intcap", Unit: "mm", Desc: "interception storage capacity", Lower: 0., Upper: 10., Default: 2.},
+			{Name: "kb", Unit: "1/h", Desc: "baseflow recession coefficient", Lower: 1e-5, Upper: 1., Default: .01, Log: true},
+			{Name: "a", Unit: "-", Desc: "sub-surface flow coefficient", Lower: 0., Upper: 10000., Default: 100.},
+			{Name: "b", Unit: "-", Desc: "sub-surface flow exponent", Lower: 0., Upper: 1., Default: .5},
+		},
+	},
+	{
+		Name: "DawdyODonnell",
+		New:  func() Lumper { return &DawdyODonnell{} },
+		Params: []Param{
+			{Name: "ksat", Unit: "mm/s", Desc: "vertical conductivity", Lower: 1e-9, Upper: 100., Default: 1e-5, Log: true, Rate: true},
+			{Name: "depintCap", Unit: "mm", Desc: "depression and interception capacity R*", Lower: 0., Upper: 1000., Default: 10.},
+			{Name: "upszCap", Unit: "mm", Desc: "upper soil zone capacity M*", Lower: 0., Upper: 2000., Default: 200.},
+			{Name: "gwCap", Unit: "mm", Desc: "lower soil zone capacity G*", Lower: 0., Upper: 2000., Default: 500.},
+			{Name: "olfk", Unit: "1/d", Desc: "overland flow recession coefficient", Lower: 1e-5, Upper: 1., Default: .5, Log: true, Step: Recession},
+			{Name: "bfk", Unit: "1/d", Desc: "baseflow recession coefficient", Lower: 1e-5, Upper: 1., Default: .05, Log: true, Step: Recession},
+		},
+	},
+	{
+		Name: "GR4J",
+		New:  func() Lumper { return &GR4J{} },
+		Params: []Param{
+			{Name: "x1", Unit: "mm", Desc: "production store capacity", Lower: 0., Upper: 1000., Default: 350.},
+			{Name: "x2", Unit: "mm/d", Desc: "groundwater exchange coefficient", Lower: -10., Upper: 10., Default: 0., Step: PerDay},
+			{Name: "x3", Unit: "mm", Desc: "routing store reference capacity", Lower: 0., Upper: 10000., Default: 90.},
+			{Name: "x4", Unit: "d", Desc: "unit hydrograph time base", Lower: .5, Upper: 10., Default: 1.7, Step: Days},
+		},
+	},
+	{
+		Name: "HBV",
+		New:  func() Lumper { return &HBV{} },
+		Params: []Param{
+			{Name: "fc", Unit: "mm", Desc: "max basin moisture storage", Lower: 0., Upper: 1000., Default: 100.},
+			{Name: "lp", Unit: "-", Desc: "soil moisture parameter", Lower: 0., Upper: 1., Default: .5},
+			{Name: "beta", Unit: "-", Desc: "soil moisture parameter", Lower: 0., Upper: 10., Default: 1.},
+			{Name: "uzl", Unit: "mm", Desc: "upper zone fast flow limit", Lower: 0., Upper: 100., Default: 10.},
+			{Name: "k0", Unit: "1/d", Desc: "fast runoff recession coefficient", Lower: 0., Upper: 1., Default: .5, Step: Recession},
+			{Name: "k1", Unit: "1/d", Desc: "slow runoff recession coefficient", Lower: 0., Upper: 1., Default: .3, Step: Recession},
+			{Name: "k2", Unit: "1/d", Desc: "baseflow recession coefficient", Lower: 0., Upper: 1., Default: .1, Step: Recession},
+			{Name: "perc", Unit: "mm/s", Desc: "upper-to-lower zone percolation", Lower: 1e-9, Upper: 100., Default: 1e-5, Log: true, Rate: true},
+			{Name: "maxbas", Unit: "d", Desc: "triangular transfer function base", Lower: 0., Upper: 10., Default: 3., Step: Days},
+		},
+	},
+	{
+		Name: "HMETS",
+		New:  func() Lumper { return &HMETS{} },
+		Params: []Param{
+			{Name: "eteff", Unit: "-", Desc: "fraction of potential evapotranspiration", Lower: .5, Upper: 2., Default: 1.},
+			{Name: "fimp", Unit: "-", Desc: "fraction impervious", Lower: 0., Upper: 1., Default: 0.},
+			{Name: "LVcap", Unit: "mm", Desc: "vadose zone capacity", Lower: 0., Upper: 1000., Default: 300.},
+			{Name: "LPcap", Unit: "mm", Desc: "phreatic zone capacity", Lower: 0., Upper: 1000., Default: 500.},
+			{Name: "cr", Unit: "-", Desc: "runoff coefficient", Lower: 0., Upper: 1., Default: .3},
+			{Name: "cv", Unit: "-", Desc: "fraction of vadose water to hypodermic flow", Lower: 0., Upper: 1., Default: .1},
+			{Name: "cvp", Unit: "-", Desc: "fraction of vadose water to recharge", Lower: 0., Upper: 1., Default: .2},
+			{Name: "cp", Unit: "-", Desc: "fraction of phreatic water to groundwater flow", Lower: 0., Upper: 1., Default: .1},
+			{Name: "sralpha", Unit: "-", Desc: "surface runoff gamma shape", Lower: 1e-4, Upper: 171., Default: .1, Log: true},
+			{Name: "srbeta", Unit: "-", Desc: "surface runoff gamma rate", Lower: 1e-4, Upper: 50., Default: .1, Log: true},
+			{Name: "dralpha", Unit: "-", Desc: "delayed runoff gamma shape", Lower: 1e-4, Upper: 171., Default: .1, Log: true},
+			{Name: "drbeta", Unit: "-", Desc: "delayed runoff gamma rate", Lower: 1e-4, Upper: 50., Default: .1, Log: true},
+		},
+	},
+	{
+		Name: "ManabeGW",
+		New:  func() Lumper { return &ManabeGW{} },
+		Params: []Param{
+			{Name: "capacity", Unit: "mm", Desc: "reservoir capacity", Lower: 0., Upper: 1000., Default: 200.},
+			{Name: "fexposed", Unit: "-", Desc: "fraction exposed to evaporative forcings", Lower: 0., Upper: 10., Default: 1.},
+			{Name: "minSto", Unit: "mm", Desc: "minimum storage", Lower: 0., Upper: 1000., Default: 20.},
+			{Name: "perc", Unit: "mm/d", Desc: "percolation rate", Lower: 1e-7, Upper: 1000., Default: 1., Log: true, Step: PerDay},
+			{Name: "kbf", Unit: "-", Desc: "baseflow retention coefficient", Lower: .9, Upper: 1., Default: .95, Step: Retention},
+		},
+	},
+	{
+		Name: "MultiLayerCapacitance",
+		New:  func() Lumper { return &MultiLayerCapacitance{} },
+		Params: []Param{
+			{Name: "coverDens", Unit: "-", Desc: "fraction vegetation cover", Lower: 0., Upper: 1., Default: .1},
+			{Name: "szDepth", Unit: "mm", Desc: "soil zone depth", Lower: 0., Upper: 1000., Default: 350.},
+			{Name: "porosity", Unit: "-", Desc: "porosity", Lower: 0., Upper: .3, Default: .3},
+			{Name: "fc", Unit: "-", Desc: "field capacity", Lower: 0., Upper: 1., Default: .01},
+			{Name: "a", Unit: "-", Desc: "drainage coefficient", Lower: 0., Upper: 100., Default: 90.},
+			{Name: "b", Unit: "-", Desc: "drainage exponent", Lower: 0., Upper: 1., Default: .6},
+			{Name: "l1", Unit: "-", Desc: "fraction of soil zone in layer 1", Lower: 0., Upper: 1., Default: 1.},
+			{Name: "l2", Unit: "-", Desc: "fraction of soil zone in layer 2", Lower: 0., Upper: 1., Default: 0.},
+			{Name: "l3", Unit: "-", Desc: "fraction of soil zone in layer 3", Lower: 0., Upper: 1., Default: 0.},
+			{Name: "kbf", Unit: "-", Desc: "baseflow retention coefficient", Lower: .85, Upper: 1., Default: .95, Step: Retention},
+		},
+	},
+	{
+		Name: "Quinn",
+		New:  func() Lumper { return &Quinn{} },
+		Params: []Param{
+			{Name: "intercepCap", Unit: "mm", Desc: "interception capacity", Lower: 0., Upper: 1000., Default: .8},
+			{Name: "impStoCap", Unit: "mm", Desc: "impervious storage capacity", Lower: 0., Upper: 1000., Default: .5},
+			{Name: "gwCap", Unit: "mm", Desc: "gravity reservoir capacity", Lower: 0., Upper: 1e5, Default: 2.},
+			{Name: "fImp", Unit: "-", Desc: "fraction impervious", Lower: 0., Upper: 1., Default: .05},
+			{Name: "ksat", Unit: "mm/d", Desc: "saturated hydraulic conductivity", Lower: 1e-9, Upper: 100., Default: 1e-5, Log: true, Step: PerDay},
+			{Name: "rootZoneDepth", Unit: "mm", Desc: "root zone depth", Lower: 0., Upper: 1000., Default: 300.},
+			{Name: "porosity", Unit: "-", Desc: "porosity", Lower: .1, Upper: .3, Default: .3},
+			{Name: "fieldCap", Unit: "-", Desc: "field capacity", Lower: 0., Upper: .1, Default: .1},
+			{Name: "f", Unit: "1/m", Desc: "conductivity decay coefficient", Lower: 0., Upper: 1., Default: 1.},
+			{Name: "alpha", Unit: "-", Desc: "recharge coefficient", Lower: 0., Upper: 1., Default: 1.},
+			{Name: "zwt", Unit: "m", Desc: "long-term average depth to watertable", Lower: 0., Upper: 10., Default: 5.},
+			{Name: "kbf", Unit: "-", Desc: "baseflow retention coefficient", Lower: .85, Upper: 1., Default: .95, Step: Retention},
+		},
+	},
+	{
+		Name: "SIXPAR",
+		New:  func() Lumper { return &SIXPAR{} },
+		Params: []Param{
+			{Name: "UM", Unit: "mm", Desc: "upper reservoir capacity", Lower: 0., Upper: 1000., Default: 10.},
+			{Name: "LM", Unit: "mm", Desc: "lower reservoir capacity", Lower: 0., Upper: 1e5, Default: 20.},
+			{Name: "UK", Unit: "1/d", Desc: "upper reservoir recession coefficient", Lower: 1e-5, Upper: 1., Default: .5, Log: true, Step: Recession},
+			{Name: "LK", Unit: "1/d", Desc: "lower reservoir recession coefficient", Lower: 1e-5, Upper: 1., Default: .1, Log: true, Step: Recession},
+			{Name: "Z", Unit: "-", Desc: "percolation coefficient", Lower: 0., Upper: 100., Default: 50.},
+			{Name: "X", Unit: "-", Desc: "percolation exponent", Lower: 0., Upper: 10., Default: 3.},
+		},
+	},
+	{
+		Name: "SPLR",
+		New:  func() Lumper { return &SPLR{} },
+		Params: []Param{
+			{Name: "r12", Unit: "-", Desc: "partition to reservoir 1", Lower: .5, Upper: 1., Default: .8},
+			{Name: "r23", Unit: "-", Desc: "partition to reservoir 2", Lower: .5, Upper: 1., Default: .8},
+			{Name: "k1", Unit: "1/d", Desc: "reservoir 1 recession coefficient", Lower: 0., Upper: 1., Default: .5, Step: Recession},
+			{Name: "k2", Unit: "1/d", Desc: "reservoir 2 recession coefficient", Lower: 0., Upper: 1., Default: .1, Step: Recession},
+			{Name: "k3", Unit: "1/d", Desc: "reservoir 3 recession coefficient", Lower: 0., Upper: 1., Default: .01, Step: Recession},
+			{Name: "x", Unit: "-", Desc: "PET factor", Lower: 0., Upper: 1., Default: 1.},
+		},
+	},
+	{
+		Name: "Tank",
+		New:  func() Lumper { return &Tank{} },
+		Params: []Param{
+			{Name: "z11", Unit: "mm", Desc: "tank 1 upper outlet height", Lower: 0., Upper: 1., Default: .5},
+			{Name: "z12", Unit: "mm", Desc: "tank 1 lower outlet height", Lower: 0., Upper: 1., Default: .25},
+			{Name: "z2", Unit: "mm", Desc: "tank 2 outlet height", Lower: 0., Upper: 1., Default: .5},
+			{Name: "z3", Unit: "mm", Desc: "tank 3 outlet height", Lower: 0., Upper: 1., Default: .5},
+			{Name: "a11", Unit: "1/d", Desc: "tank 1 upper outlet coefficient", Lower: 0., Upper: 1., Default: .2, Step: Recession},
+			{Name: "a12", Unit: "1/d", Desc: "tank 1 lower outlet coefficient", Lower: 0., Upper: 1., Default: .1, Step: Recession},
+			{Name: "a2", Unit: "1/d", Desc: "tank 2 outlet coefficient", Lower: 0., Upper: 1., Default: .1, Step: Recession},
+			{Name: "a3", Unit: "1/d", Desc: "tank 3 outlet coefficient", Lower: 0., Upper: 1., Default: .05, Step: Recession},
+			{Name: "a4", Unit: "1/d", Desc: "tank 4 outlet coefficient", Lower: 0., Upper: 1., Default: .01, Step: Recession},
+			{Name: "b1", Unit: "1/d", Desc: "tank 1 infiltration coefficient", Lower: 0., Upper: 1., Default: .1, Step: Recession},
+			{Name: "b2", Unit: "1/d", Desc: "tank 2 percolation coefficient", Lower: 0., Upper: 1., Default: .05, Step: Recession},
+			{Name: "b3", Unit: "1/d", Desc: "tank 3 deep percolation coefficient", Lower: 0., Upper: 1., Default: .01, Step: Recession},
+		},
+	}, {
+		Name: "SACSMA",
+		New:  func() Lumper { return &SACSMA{} },
+		Params: []Param{
+			{Name: "uztwm", Unit: "mm", Desc: "upper zone tension water capacity", Lower: 1., Upper: 150., Default: 50.},
+			{Name: "uzfwm", Unit: "mm", Desc: "upper zone free water capacity", Lower: 1., Upper: 150., Default: 40.},
+			{Name: "uzk", Unit: "1/d", Desc: "upper zone free water depletion rate", Lower: .1, Upper: .5, Default: .3, Step: Recession},
+			{Name: "pctim", Unit: "-", Desc: "permanently impervious fraction", Lower: 0., Upper: .1, Default: .01},
+			{Name: "adimp", Unit: "-", Desc: "additional impervious fraction", Lower: 0., Upper: .4, Default: .05},
+			{Name: "zperc", Unit: "-", Desc: "maximum percolation rate coefficient", Lower: 1., Upper: 250., Default: 40.},
+			{Name: "rexp", Unit: "-", Desc: "percolation equation exponent", Lower: 1., Upper: 5., Default: 2.},
+			{Name: "lztwm", Unit: "mm", Desc: "lower zone tension water capacity", Lower: 1., Upper: 500., Default: 150.},
+			{Name: "lzfsm", Unit: "mm", Desc: "lower zone supplemental free water capacity", Lower: 1., Upper: 1000., Default: 50.},
+			{Name: "lzfpm", Unit: "mm", Desc: "lower zone primary free water capacity", Lower: 1., Upper: 1000., Default: 150.},
+			{Name: "lzsk", Unit: "1/d", Desc: "lower zone supplemental free water depletion rate", Lower: .01, Upper: .25, Default: .05, Step: Recession},
+			{Name: "lzpk", Unit: "1/d", Desc: "lower zone primary free water depletion rate", Lower: .0001, Upper: .025, Default: .005, Log: true, Step: Recession},
+			{Name: "pfree", Unit: "-", Desc: "fraction of percolation to lower zone free water", Lower: 0., Upper: .6, Default: .1},
+			{Name: "side", Unit: "-", Desc: "ratio of deep recharge to channel baseflow", Lower: 0., Upper: .5, Default: 0.},
+		},
+	},
+	{
+		Name: "HYMOD",
+		New:  func() Lumper { return &HYMOD{} },
+		Params: []Param{
+			{Name: "cmax", Unit: "mm", Desc: "maximum storage capacity", Lower: 1., Upper: 1000., Default: 300.},
+			{Name: "bexp", Unit: "-", Desc: "spatial variability of storage capacity", Lower: 0., Upper: 2., Default: .5},
+			{Name: "alpha", Unit: "-", Desc: "fraction of excess routed as quick-flow", Lower: 0., Upper: 1., Default: .5},
+			{Name: "kquick", Unit: "1/d", Desc: "quick-flow reservoir recession coefficient", Lower: .1, Upper: 1., Default: .5, Step: Recession},
+			{Name: "kslow", Unit: "1/d", Desc: "slow-flow reservoir recession coefficient", Lower: 0., Upper: .1, Default: .01, Step: Recession},
+		},
+	},
+	{
+		Name: "IHACRES",
+		New:  func() Lumper { return &IHACRES{} },
+		Params: []Param{
+			{Name: "f", Unit: "-", Desc: "stress threshold, as a multiple of d", Lower: .01, Upper: 3., Default: .7},
+			{Name: "e", Unit: "-", Desc: "PET to ET modulation", Lower: .1, Upper: 1.5, Default: 1.},
+			{Name: "d", Unit: "mm", Desc: "flow threshold moisture deficit", Lower: 50., Upper: 550., Default: 200.},
+			{Name: "tauq", Unit: "d", Desc: "quick-flow time constant", Lower: .5, Upper: 10., Default: 2., Step: Days},
+			{Name: "taus", Unit: "d", Desc: "slow-flow time constant", Lower: 10., Upper: 1000., Default: 50., Log: true, Step: Days},
+			{Name: "vs", Unit: "-", Desc: "fraction of effective rainfall to slow-flow", Lower: 0., Upper: 1., Default: .5},
+		},
+	},
+	{
+		Name: "AWBM",
+		New:  func() Lumper { return &AWBM{} },
+		Params: []Param{
+			{Name: "c1", Unit: "mm", Desc: "surface store 1 capacity", Lower: 0., Upper: 50., Default: 7.},
+			{Name: "c2", Unit: "mm", Desc: "surface store 2 capacity", Lower: 0., Upper: 200., Default: 70.},
+			{Name: "c3", Unit: "mm", Desc: "surface store 3 capacity", Lower: 0., Upper: 500., Default: 150.},
+			{Name: "a1", Unit: "-", Desc: "surface store 1 partial area", Lower: 0., Upper: 1., Default: .134},
+			{Name: "a2", Unit: "-", Desc: "surface store 2 partial area", Lower: 0., Upper: 1., Default: .433},
+			{Name: "a3", Unit: "-", Desc: "surface store 3 partial area", Lower: 0., Upper: 1., Default: .433},
+			{Name: "bfi", Unit: "-", Desc: "baseflow index", Lower: 0., Upper: 1., Default: .35},
+			{Name: "kbase", Unit: "-", Desc: "baseflow recession constant", Lower: .9, Upper: 1., Default: .95, Step: Retention},
+			{Name: "ksurf", Unit: "-", Desc: "surface runoff recession constant", Lower: 0., Upper: 1., Default: .35, Step: Retention},
+		},
+	},
+	{
+		Name: "Xinanjiang",
+		New:  func() Lumper { return &Xinanjiang{} },
+		Params: []Param{
+			{Name: "wm", Unit: "mm", Desc: "tension water capacity", Lower: 50., Upper: 500., Default: 150.},
+			{Name: "x", Unit: "-", Desc: "upper layer fraction of tension water capacity", Lower: .05, Upper: .5, Default: .15},
+			{Name: "y", Unit: "-", Desc: "lower layer fraction of remaining tension water capacity", Lower: .1, Upper: .9, Default: .7},
+			{Name: "b", Unit: "-", Desc: "tension water capacity curve exponent", Lower: .05, Upper: 2., Default: .3},
+			{Name: "c", Unit: "-", Desc: "deep layer evapotranspiration coefficient", Lower: 0., Upper: .3, Default: .15},
+			{Name: "im", Unit: "-", Desc: "impervious fraction", Lower: 0., Upper: .1, Default: .01},
+			{Name: "sm", Unit: "mm", Desc: "free water storage capacity", Lower: 1., Upper: 100., Default: 20.},
+			{Name: "ex", Unit: "-", Desc: "free water capacity curve exponent", Lower: .5, Upper: 2., Default: 1.5},
+			{Name: "kig", Unit: "1/d", Desc: "free water outflow coefficient", Lower: .1, Upper: .9, Default: .7, Step: Recession},
+			{Name: "kif", Unit: "-", Desc: "fraction of free water outflow to interflow", Lower: 0., Upper: 1., Default: .5},
+			{Name: "ci", Unit: "-", Desc: "interflow recession constant", Lower: 0., Upper: .99, Default: .7, Step: Retention},
+			{Name: "cg", Unit: "-", Desc: "groundwater recession constant", Lower: .9, Upper: .999, Default: .98, Step: Retention},
+		},
+	},
+}
@@ -113,3 +113,22 @@ func (m *MultiLayerCapacitance) Storage() float64 {
 
 // // Ndim returns the number of dimensions
 // func (m *MultiLayerCapacitance) Ndim() int { return 9 }
+
+// State returns the model state: [s1, s2, s3, bf]
+func (m *MultiLayerCapacitance) State() []float64 {
+	return []float64{m.s1.sto, m.s2.sto, m.s3.sto, m.bf.sto}
+}
+
+// SetState restores the model state returned by State()
+func (m *MultiLayerCapacitance) SetState(x []float64) error {
+	if err := checkState("MultiLayerCapacitance", x, 4); err != nil {
+		return err
+	}
+	m.s1.sto, m.s2.sto, m.s3.sto, m.bf.sto = x[0], x[1], x[2], x[3]
+	return nil
+}
+
+// StateBounds returns the bounds of the state variables returned by State()
+func (m *MultiLayerCapacitance) StateBounds() (lo, hi []float64) {
+	return storages(m.s1.cap, m.s2.cap, m.s3.cap, unbounded)
+}
@@ -0,0 +1,174 @@
+package optimize
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/maseology/glbopt"
+	"github.com/maseology/goHydro/internal/rngseed"
+	rr "github.com/maseology/goHydro/rainrun"
+)
+
+// Mode of multi-objective calibration
+type Mode int
+
+const (
+	WeightedSum Mode = iota // objectives are combined by weighted average and minimized using SCE
+	Pareto                  // the Pareto front is sought using NSGA-II
+)
+
+// Config of a calibration run
+type Config struct {
+	Model      string      // registered model name (see rainrun.Models())
+	Objectives []Objective // default: NSE
+	Mode       Mode
+	Warmup     int                    // number of timesteps simulated before scoring begins
+	Start, End time.Time              // calibration window (inclusive); zero values extend to the ends of the record
+	Include    func(t time.Time) bool // optional: limits scoring to the dates within the window for which Include is true
+	Ncmplx     int                    // number of SCE complexes (WeightedSum); default: 200
+	Npop, Ngen int                    // NSGA-II population size and number of generations (Pareto); default: 100, 250
+	Seed       int64                  // random seed; 0: seeded from the clock (see Result.Seed)
+}
+
+// Solution is a single calibrated parameter set
+type Solution struct {
+	U []float64 // sample space [0,1]
+	P []float64 // parameter values
+	F []float64 // objective function values, ordered as Config.Objectives
+}
+
+// Result of a calibration run
+type Result struct {
+	Model      string
+	Params     []string
+	Objectives []string
+	Best       Solution   // optimum of the weighted-sum (in Pareto mode, the front member of lowest weighted sum)
+	Front      []Solution // Pareto mode only: non-dominated solutions sorted by the first objective
+	Warmup     int
+	Start, End time.Time // scoring period
+	Nscored    int       // number of (observed) timesteps scored
+	Coverage   float64   // fraction of the scoring period observed
+	Seed       int64     // random seed, repeating the calibration when passed to Config.Seed
+	Npenalized int       // number of evaluations returning NaN objectives, penalized
+	Elapsed    time.Duration
+}
+
+// Calibrate a rainrun model to the forcing data
+func Calibrate(frc *rr.Frc, cfg Config) (*Result, error) {
+	if len(cfg.Objectives) == 0 {
+		cfg.Objectives = []Objective{NSE(1.)}
+	}
+	ws := 0.
+	for _, o := range cfg.Objectives {
+		if o.F == nil {
+			return nil, fmt.Errorf("optimize.Calibrate: objective %s has no function", o.Name)
+		}
+		if o.Weight < 0. {
+			return nil, fmt.Errorf("optimize.Calibrate: objective %s has a negative weight (%g)", o.Name, o.Weight)
+		}
+		ws += o.Weight
+	}
+	if ws <= 0. {
+		return nil, fmt.Errorf("optimize.Calibrate: objective weights must sum to greater than zero")
+	}
+	if cfg.Ncmplx <= 0 {
+		cfg.Ncmplx = ncmplx
+	}
+	if cfg.Npop <= 0 {
+		cfg.Npop = 100
+	}
+	if cfg.Ngen <= 0 {
+		cfg.Ngen = 250
+	}
+	p, err := newProblem(frc, &cfg)
+	if err != nil {
+		return nil, err
+	}
+	if p.mi.NeedsTimestep && frc.Timestep <= 0. {
+		return nil, fmt.Errorf("optimize.Calibrate: %s requires the forcing timestep (Frc.Timestep) to be set", cfg.Model)
+	}
+	ndim := p.mi.Ndim()
+	if n := len(p.smpl(make([]float64, ndim), frc.Timestep)); n != ndim {
+		return nil, fmt.Errorf("optimize.Calibrate: %s sampler returns %d parameters, %d expected", cfg.Model, n, ndim)
+	}
+
+	res := Result{
+		Model:    cfg.Model,
+		Params:   p.mi.ParamNames(),
+		Warmup:   cfg.Warmup,
+		Nscored:  p.nscore,
+		Coverage: float64(p.nscore) / float64(p.nwindow),
+	}
+	for _, o := range cfg.Objectives {
+		res.Objectives = append(res.Objectives, o.Name)
+	}
+	if len(frc.DT) == len(frc.D) {
+		res.Start, res.End = frc.DT[p.i0], frc.DT[p.i1-1]
+	}
+	solution := func(u []float64) Solution {
+		return Solution{U: u, P: p.smpl(u, frc.Timestep), F: p.evaluate(u)}
+	}
+
+	rng, seed := rngseed.New(cfg.Seed)
+	res.Seed = seed
+
+	tt := time.Now()
+	switch cfg.Mode {
+	case WeightedSum:
+		uFinal, _ := glbopt.SCE(cfg.Ncmplx, ndim, rng, p.weighted, true)
+		res.Best = solution(uFinal)
+	case Pareto:
+		front := nsga2(ndim, cfg.Npop, cfg.Ngen, rng, p.evaluate)
+		res.Front = make([]Solution, len(front))
+		for i, ind := range front {
+			res.Front[i] = Solution{U: ind.u, P: p.smpl(ind.u, frc.Timestep), F: ind.f}
+		}
+		sort.Slice(res.Front, func(i, j int) bool { return res.Front[i].F[0] < res.Front[j].F[0] })
+		ib, fb := 0, 0.
+		for i, s := range res.Front {
+			if f := weightedSum(cfg.Objectives, s.F); i == 0 || f < fb {
+				ib, fb = i, f
+			}
+		}
+		res.Best = res.Front[ib]
+	default:
+		return nil, fmt.Errorf("optimize.Calibrate: unknown calibration mode %d", cfg.Mode)
+	}
+	res.Elapsed = time.Since(tt)
+	res.Npenalized = int(p.nnan.Load())
+	return &res, nil
+}
+
+func weightedSum(objs []Objective, f []float64) float64 {
+	ws, s := 0., 0.
+	for i, o := range objs {
+		s += o.Weight * f[i]
+		ws += o.Weight
+	}
+	return s / ws
+}
+
+// String summarizes the calibration result
+func (r *Result) String() string {
+	s := fmt.Sprintf("%s calibrated over %d objective(s), %v elapsed\n", r.Model, len(r.Objectives), r.Elapsed)
+	if !r.Start.IsZero() {
+		s += fmt.Sprintf(" scoring period: %s to %s (%d timestep warm-up)\n", r.Start.Format("2006-01-02"), r.End.Format("2006-01-02"), r.Warmup)
+	}
+	s += fmt.Sprintf(" scored on %d timesteps, %.1f%% coverage\n", r.Nscored, 100.*r.Coverage)
+	s += fmt.Sprintf(" seed: %d\n", r.Seed)
+	if r.Npenalized > 0 {
+		s += fmt.Sprintf(" ** Warning: %d objective evaluations returned NaN and were penalized **\n", r.Npenalized)
+	}
+	s += "Optimum:\n"
+	for i, v := range r.Params {
+		s += fmt.Sprintf(" %10s: %10.4f\t[%.4e]\n", v, r.Best.P[i], r.Best.U[i])
+	}
+	for i, v := range r.Objectives {
+		s += fmt.Sprintf(" %10s: %10.4f\n", v, r.Best.F[i])
+	}
+	if len(r.Front) > 0 {
+		s += fmt.Sprintf(" Pareto front: %d solutions\n", len(r.Front))
+	}
+	return s
+}
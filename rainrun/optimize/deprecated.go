@@ -0,0 +1,39 @@
+package optimize
+
+import (
+	"log"
+
+	rr "github.com/maseology/goHydro/rainrun"
+	"github.com/maseology/mmio"
+)
+
+var gfrc *rr.Frc // forcing data of the last call to Optimize, used by the deprecated optimizers below
+
+// CCFGR4J optimizes the GR4J model coupled to the CCF snowpack model, using
+// the forcing data last passed to Optimize.
+//
+// Deprecated: use OptimizeConfig with Config{Model: "GR4J+CCF"}.
+func CCFGR4J(logfp string) { optimizeLegacy(logfp, "GR4J+CCF") }
+
+// CCFHBV optimizes the HBV model coupled to the CCF snowpack model, using
+// the forcing data last passed to Optimize.
+//
+// Deprecated: use OptimizeConfig with Config{Model: "HBV+CCF"}.
+func CCFHBV(logfp string) { optimizeLegacy(logfp, "HBV+CCF") }
+
+// MakkinkCCFGR4J optimizes the GR4J model coupled to the CCF snowpack model
+// and Makkink PET, using the forcing data last passed to Optimize.
+//
+// Deprecated: use OptimizeConfig with Config{Model: "GR4J+CCF+Makkink"}.
+func MakkinkCCFGR4J(logfp string) { optimizeLegacy(logfp, "GR4J+CCF+Makkink") }
+
+func optimizeLegacy(logfp, mdl string) {
+	if gfrc == nil {
+		log.Fatalf("optimize.%s: no forcing data, see Optimize", mdl)
+	}
+	res, err := OptimizeConfig(gfrc, Config{Model: mdl, Warmup: gfrc.Year()})
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	mmio.GetInstance(logfp).Print(res)
+}
@@ -1,147 +1,122 @@
 package optimize
 
 import (
+	"fmt"
 	"log"
 	"math"
+	"sync"
+	"sync/atomic"
 
 	rr "github.com/maseology/goHydro/rainrun"
 	"github.com/maseology/goHydro/rainrun/sample"
 )
 
-func eval(m rr.Lumper) float64 { // evaluate model
-	o := make([]float64, gfrc.Ndt)
-	s := make([]float64, gfrc.Ndt)
-	for i, v := range gfrc.D {
-		y := v.Yield()
-		_, r, _ := m.Update(y, v.Ep)
-		o[i] = v.Q
-		s[i] = r
-	}
-	return minimizer(o[365:], s[365:])
+// problem holds everything needed to evaluate a model sample
+type problem struct {
+	frc        *rr.Frc
+	mi         *rr.ModelInfo
+	smpl       sample.Sampler
+	objs       []Objective
+	s0, i0, i1 int     // simulation start, scoring start, and end (exclusive) timestep indices
+	incl       []bool  // timesteps scored (nil: all)
+	msk        rr.Mask // observed timesteps; gaps are not scored
+	nscore     int     // number of timesteps scored
+	nwindow    int     // number of timesteps in the calibration window, including gaps
+	nnan       atomic.Int64
+	nanOnce    sync.Once
+	errOnce    sync.Once
 }
 
-func genAtkinson(u []float64) float64 {
-	var m rr.Lumper = &rr.Atkinson{}
-	m.New(sample.Atkinson(u)...)
-	f := eval(m)
-	if math.IsNaN(f) {
-		log.Fatalf("Objective function error, u: %v\n", u)
+func newProblem(frc *rr.Frc, cfg *Config) (*problem, error) {
+	mi, ok := rr.Lookup(cfg.Model)
+	if !ok {
+		return nil, fmt.Errorf("optimize: unrecognized model: %s", cfg.Model)
 	}
-	return f
-}
-
-func genDawdyODonnell(u []float64) float64 {
-	var m rr.Lumper = &rr.DawdyODonnell{}
-	m.New(sample.DawdyODonnell(u, gfrc.Timestep)...)
-	f := eval(m)
-	if math.IsNaN(f) {
-		log.Fatalf("Objective function error, u: %v\n", u)
+	smpl, err := sample.Get(cfg.Model)
+	if err != nil {
+		return nil, err
 	}
-	return f
-}
-
-func genGR4J(u []float64) float64 {
-	var m rr.Lumper = &rr.GR4J{}
-	m.New(sample.GR4J(u)...)
-	f := eval(m)
-	if math.IsNaN(f) {
-		log.Fatalf("Objective function error, u: %v\n", u)
+	p := problem{frc: frc, mi: mi, smpl: smpl, objs: cfg.Objectives, i1: len(frc.D)}
+	if len(frc.DT) == len(frc.D) {
+		for i, dt := range frc.DT {
+			if !cfg.Start.IsZero() && dt.Before(cfg.Start) {
+				p.i0 = i + 1
+			}
+			if !cfg.End.IsZero() && dt.After(cfg.End) {
+				p.i1 = i
+				break
+			}
+		}
+	} else if !cfg.Start.IsZero() || !cfg.End.IsZero() || cfg.Include != nil {
+		return nil, fmt.Errorf("optimize: calibration window given without forcing dates")
 	}
-	return f
-}
-
-// func genGR4J(u []float64) float64 {
-// 	var m rr.Lumper = &rr.GR4J{}
-// 	ss := sampler.NewSet(sample.GR4J()) //////////////////////////////////  TO FIX
-// 	m.New(ss.Sample(u)...)
-// 	f := eval(m)
-// 	if math.IsNaN(f) {
-// 		log.Fatalf("Objective function error, u: %v\n", u)
-// 	}
-// 	return f
-// }
-
-func genHBV(u []float64) float64 {
-	var m rr.Lumper = &rr.HBV{}
-	m.New(sample.HBV(u, gfrc.Timestep)...)
-	f := eval(m)
-	if math.IsNaN(f) {
-		// log.Fatalf("Objective function error, u: %v\n", u)
-		return 1000.
+	p.s0 = max(0, p.i0-cfg.Warmup)
+	p.i0 = max(p.i0, p.s0+cfg.Warmup)
+	p.msk = frc.Mask()
+	if cfg.Include != nil {
+		p.incl = make([]bool, len(frc.D))
 	}
-	return f
-}
-
-func genHMETS(u []float64) float64 {
-	var m rr.Lumper = &rr.HMETS{}
-	m.New(sample.HMETS(u)...)
-	f := eval(m)
-	if math.IsNaN(f) {
-		// log.Fatalf("Objective function error, u: %v\n", u)
-		return 1000.
+	for i := p.i0; i < p.i1; i++ {
+		if cfg.Include != nil {
+			if !cfg.Include(frc.DT[i]) {
+				continue
+			}
+			p.incl[i] = true
+		}
+		p.nwindow++
+		if p.msk[i] {
+			p.nscore++
+		}
 	}
-	return f
-}
-
-func genManabeGW(u []float64) float64 {
-	var m rr.Lumper = &rr.ManabeGW{}
-	m.New(sample.ManabeGW(u)...)
-	f := eval(m)
-	if math.IsNaN(f) {
-		// log.Fatalf("Objective function error, u: %v\n", u)
-		return 1000.
+	if p.nscore < 2 {
+		return nil, fmt.Errorf("optimize: calibration window of %d observed timesteps (of %d) following a %d timestep warm-up is too short", p.nscore, p.nwindow, cfg.Warmup)
 	}
-	return f
+	return &p, nil
 }
 
-func genMultiLayerCapacitance(u []float64) float64 {
-	var m rr.Lumper = &rr.MultiLayerCapacitance{}
-	m.New(sample.MultiLayerCapacitance(u)...)
-	f := eval(m)
-	if math.IsNaN(f) {
-		// log.Fatalf("Objective function error, u: %v\n", u)
-		return 1000.
+// simulate returns the observed and simulated runoff over the calibration window, excluding gaps
+func (p *problem) simulate(m rr.Model) (o, s []float64) {
+	o = make([]float64, 0, p.nscore)
+	s = make([]float64, 0, p.nscore)
+	for i := p.s0; i < p.i1; i++ {
+		v := p.frc.D[i]
+		_, _, r, _ := m.Update(&v)
+		if i >= p.i0 && (p.incl == nil || p.incl[i]) && p.msk[i] {
+			o = append(o, v.Q)
+			s = append(s, r)
+		}
 	}
-	return f
+	return
 }
 
-func genQuinn(u []float64) float64 {
-	var m rr.Lumper = &rr.Quinn{}
-	m.New(sample.Quinn(u)...)
-	f := eval(m)
-	if math.IsNaN(f) {
-		log.Fatalf("Objective function error, u: %v\n", u)
-	}
-	return f
-}
+// nanPenalty replaces NaN objective function values
+const nanPenalty = 1000.
 
-func genSIXPAR(u []float64) float64 {
-	var m rr.Lumper = &rr.SIXPAR{}
-	m.New(sample.SIXPAR(u)...)
-	f := eval(m)
-	if math.IsNaN(f) {
-		// log.Fatalf("Objective function error, u: %v\n", u)
-		return 1000.
+// evaluate returns the objective function values of a sample taken from the unit hypercube
+func (p *problem) evaluate(u []float64) []float64 {
+	f := make([]float64, len(p.objs))
+	m, err := p.mi.BuildFor(p.frc.Timestep, p.smpl(u, p.frc.Timestep)...)
+	if err != nil {
+		panic(err) // sampler and model dimensions are checked by Calibrate
 	}
-	return f
-}
-
-func genSPLR(u []float64) float64 {
-	var m rr.Lumper = &rr.SPLR{}
-	m.New(sample.SPLR(u)...)
-	f := eval(m)
-	if math.IsNaN(f) {
-		log.Fatalf("Objective function error, u: %v\n", u)
+	o, s := p.simulate(m)
+	if err := m.(*rr.Coupled).Err(); err != nil {
+		p.errOnce.Do(func() {
+			log.Printf("optimize: %s snowpack: %v; precipitation passed through as yield\n", p.mi.Name, err)
+		})
+	}
+	for i, obj := range p.objs {
+		f[i] = obj.F(o, s)
+		if math.IsNaN(f[i]) {
+			p.nanOnce.Do(func() { log.Printf("optimize: %s returned NaN at u = %.4f, penalized (%g)\n", obj.Name, u, nanPenalty) })
+			p.nnan.Add(1)
+			f[i] = nanPenalty
+		}
 	}
 	return f
 }
 
-func genTank(u []float64) float64 {
-	var m rr.Lumper = &rr.Tank{}
-	m.New(sample.Tank(u)...)
-	f := eval(m)
-	if math.IsNaN(f) {
-		log.Fatalf("Objective function error, u: %v\n", u)
-	}
-	return f
+// weighted returns the weighted-sum objective function
+func (p *problem) weighted(u []float64) float64 {
+	return weightedSum(p.objs, p.evaluate(u))
 }
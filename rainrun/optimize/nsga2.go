@@ -0,0 +1,222 @@
+package optimize
+
+import (
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// NSGA-II: Deb K., A. Pratap, S. Agarwal, T. Meyarivan, 2002. A fast and elitist multiobjective genetic algorithm: NSGA-II. IEEE Transactions on Evolutionary Computation 6(2). pp. 182-197.
+const (
+	etac = 15. // SBX crossover distribution index
+	etam = 20. // polynomial mutation distribution index
+	pc   = .9  // crossover probability
+)
+
+type individual struct {
+	u, f  []float64
+	rank  int
+	crowd float64
+}
+
+func (a *individual) dominates(b *individual) bool {
+	better := false
+	for k := range a.f {
+		if a.f[k] > b.f[k] {
+			return false
+		}
+		if a.f[k] < b.f[k] {
+			better = true
+		}
+	}
+	return better
+}
+
+// nsga2 returns the non-dominated set of samples taken from the ndim unit hypercube
+func nsga2(ndim, npop, ngen int, rng *rand.Rand, fun func(u []float64) []float64) []individual {
+	pop := make([]individual, npop)
+	for i := range pop {
+		pop[i].u = make([]float64, ndim)
+		for j := range ndim {
+			pop[i].u[j] = rng.Float64()
+		}
+	}
+	evaluatePopulation(pop, fun)
+	rankPopulation(pop)
+
+	for range ngen {
+		off := make([]individual, 0, npop)
+		for len(off) < npop {
+			c1, c2 := crossover(tournament(pop, rng), tournament(pop, rng), rng)
+			mutate(c1, rng)
+			mutate(c2, rng)
+			off = append(off, individual{u: c1}, individual{u: c2})
+		}
+		off = off[:npop]
+		evaluatePopulation(off, fun)
+		pop = selectSurvivors(append(pop, off...), npop)
+	}
+
+	var front []individual
+	for _, p := range pop {
+		if p.rank == 0 {
+			front = append(front, p)
+		}
+	}
+	return front
+}
+
+func evaluatePopulation(pop []individual, fun func(u []float64) []float64) {
+	var wg sync.WaitGroup
+	ch := make(chan int)
+	for range runtime.GOMAXPROCS(0) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range ch {
+				pop[i].f = fun(pop[i].u)
+			}
+		}()
+	}
+	for i := range pop {
+		ch <- i
+	}
+	close(ch)
+	wg.Wait()
+}
+
+// rankPopulation assigns non-domination rank and crowding distance; returns the fronts
+func rankPopulation(pop []individual) [][]int {
+	n := len(pop)
+	sdom := make([][]int, n) // set of solutions dominated by i
+	ndom := make([]int, n)   // number of solutions dominating i
+	fronts := [][]int{{}}
+	for i := range n {
+		for j := range n {
+			if i == j {
+				continue
+			}
+			if pop[i].dominates(&pop[j]) {
+				sdom[i] = append(sdom[i], j)
+			} else if pop[j].dominates(&pop[i]) {
+				ndom[i]++
+			}
+		}
+		if ndom[i] == 0 {
+			pop[i].rank = 0
+			fronts[0] = append(fronts[0], i)
+		}
+	}
+	for k := 0; len(fronts[k]) > 0; k++ {
+		var next []int
+		for _, i := range fronts[k] {
+			for _, j := range sdom[i] {
+				ndom[j]--
+				if ndom[j] == 0 {
+					pop[j].rank = k + 1
+					next = append(next, j)
+				}
+			}
+		}
+		fronts = append(fronts, next)
+	}
+	fronts = fronts[:len(fronts)-1]
+	for _, fr := range fronts {
+		crowding(pop, fr)
+	}
+	return fronts
+}
+
+func crowding(pop []individual, fr []int) {
+	for _, i := range fr {
+		pop[i].crowd = 0.
+	}
+	if len(fr) == 0 {
+		return
+	}
+	for k := range pop[fr[0]].f {
+		sort.Slice(fr, func(a, b int) bool { return pop[fr[a]].f[k] < pop[fr[b]].f[k] })
+		lo, hi := pop[fr[0]].f[k], pop[fr[len(fr)-1]].f[k]
+		pop[fr[0]].crowd = math.Inf(1)
+		pop[fr[len(fr)-1]].crowd = math.Inf(1)
+		if hi == lo {
+			continue
+		}
+		for j := 1; j < len(fr)-1; j++ {
+			pop[fr[j]].crowd += (pop[fr[j+1]].f[k] - pop[fr[j-1]].f[k]) / (hi - lo)
+		}
+	}
+}
+
+func selectSurvivors(pop []individual, npop int) []individual {
+	o := make([]individual, 0, npop)
+	for _, fr := range rankPopulation(pop) {
+		if len(o)+len(fr) > npop {
+			sort.Slice(fr, func(a, b int) bool { return pop[fr[a]].crowd > pop[fr[b]].crowd })
+			fr = fr[:npop-len(o)]
+		}
+		for _, i := range fr {
+			o = append(o, pop[i])
+		}
+		if len(o) == npop {
+			break
+		}
+	}
+	return o
+}
+
+// tournament binary selection by rank, then crowding distance
+func tournament(pop []individual, rng *rand.Rand) []float64 {
+	a, b := &pop[rng.Intn(len(pop))], &pop[rng.Intn(len(pop))]
+	if a.rank < b.rank || (a.rank == b.rank && a.crowd > b.crowd) {
+		return a.u
+	}
+	return b.u
+}
+
+// crossover simulated binary (SBX)
+func crossover(p1, p2 []float64, rng *rand.Rand) ([]float64, []float64) {
+	c1, c2 := append([]float64(nil), p1...), append([]float64(nil), p2...)
+	if rng.Float64() > pc {
+		return c1, c2
+	}
+	for j := range c1 {
+		if rng.Float64() > .5 {
+			continue
+		}
+		u := rng.Float64()
+		var b float64
+		if u <= .5 {
+			b = math.Pow(2.*u, 1./(etac+1.))
+		} else {
+			b = math.Pow(1./(2.*(1.-u)), 1./(etac+1.))
+		}
+		c1[j] = clamp(.5 * ((1.+b)*p1[j] + (1.-b)*p2[j]))
+		c2[j] = clamp(.5 * ((1.-b)*p1[j] + (1.+b)*p2[j]))
+	}
+	return c1, c2
+}
+
+// mutate polynomial mutation
+func mutate(c []float64, rng *rand.Rand) {
+	pm := 1. / float64(len(c))
+	for j := range c {
+		if rng.Float64() > pm {
+			continue
+		}
+		u := rng.Float64()
+		var d float64
+		if u < .5 {
+			d = math.Pow(2.*u, 1./(etam+1.)) - 1.
+		} else {
+			d = 1. - math.Pow(2.*(1.-u), 1./(etam+1.))
+		}
+		c[j] = clamp(c[j] + d)
+	}
+}
+
+func clamp(v float64) float64 {
+	return math.Min(1., math.Max(0., v))
+}
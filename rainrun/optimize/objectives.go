@@ -0,0 +1,77 @@
+package optimize
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/maseology/objfunc"
+)
+
+// Objective is a calibration criterion; F returns a value to be minimized, where 0 is a perfect fit
+type Objective struct {
+	Name   string
+	Weight float64 // weighting applied in WeightedSum mode
+	F      func(o, s []float64) float64
+}
+
+// NSE returns the 1-NSE objective
+func NSE(w float64) Objective {
+	return Objective{"NSE", w, func(o, s []float64) float64 { return 1. - objfunc.NSE(o, s) }}
+}
+
+// KGE returns the 1-KGE objective
+func KGE(w float64) Objective {
+	return Objective{"KGE", w, func(o, s []float64) float64 { return 1. - objfunc.KGE(o, s) }}
+}
+
+// LogNSE returns the 1-NSE objective computed on log-transformed flows, emphasizing low-flows
+func LogNSE(w float64) Objective {
+	return Objective{"logNSE", w, func(o, s []float64) float64 { return 1. - objfunc.NSE(logFlows(o), logFlows(s)) }}
+}
+
+// Bias returns the absolute bias objective
+func Bias(w float64) Objective {
+	return Objective{"bias", w, func(o, s []float64) float64 { return math.Abs(objfunc.Bias(o, s)) }}
+}
+
+// FDC returns the flow-duration curve error: the root-mean-square difference
+// between the log-transformed observed and simulated flow-duration curves
+func FDC(w float64) Objective {
+	return Objective{"FDC", w, func(o, s []float64) float64 {
+		lo, ls := logFlows(o), logFlows(s)
+		sort.Float64s(lo)
+		sort.Float64s(ls)
+		ss := 0.
+		for i := range lo {
+			ss += (lo[i] - ls[i]) * (lo[i] - ls[i])
+		}
+		return math.Sqrt(ss / float64(len(lo)))
+	}}
+}
+
+// ObjectiveByName returns a named objective: NSE, KGE, logNSE, bias or FDC
+func ObjectiveByName(name string, w float64) (Objective, error) {
+	switch name {
+	case "NSE":
+		return NSE(w), nil
+	case "KGE":
+		return KGE(w), nil
+	case "logNSE":
+		return LogNSE(w), nil
+	case "bias":
+		return Bias(w), nil
+	case "FDC":
+		return FDC(w), nil
+	}
+	return Objective{}, fmt.Errorf("optimize: unrecognized objective: %s", name)
+}
+
+func logFlows(q []float64) []float64 {
+	const eps = 1e-6 // avoids log(0)
+	l := make([]float64, len(q))
+	for i, v := range q {
+		l[i] = math.Log(math.Max(v, 0.) + eps)
+	}
+	return l
+}
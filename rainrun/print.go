@@ -22,7 +22,7 @@ func SumHydrograph(frc *Frc, o, s, g []float64, prfx string) {
 func SumMonthly(dt []time.Time, o, s []float64, ts, ca float64, prfx string) {
 	tso, tss := make(mmio.TimeSeries, len(dt)), make(mmio.TimeSeries, len(dt))
 	for i, d := range dt {
-		if math.IsNaN(o[i]) || math.IsNaN(s[i]) {
+		if !observed(o[i]) || math.IsNaN(s[i]) {
 			continue
 		}
 		tso[d] = o[i]
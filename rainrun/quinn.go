@@ -29,7 +29,7 @@ func (m *Quinn) New(p ...float64) {
 		p[7] = .1
 		p[8] = 1.
 		p[9] = 1.
-		p[10] = 50.
+		p[10] = 5. // within the sampled range (see rainrun/sample)
 		p[11] = .95
 	}
 	if fracCheck(p[3]) || p[7] > p[6] || p[4] < 0. {
@@ -119,3 +119,22 @@ func (m *Quinn) Storage() float64 {
 
 // // Ndim returns the number of dimensions
 // func (m *Quinn) Ndim() int { return 11 }
+
+// State returns the model state: [intc, imp, sz, grav, bf]
+func (m *Quinn) State() []float64 {
+	return []float64{m.intc.sto, m.imp.sto, m.sz.sto, m.grav.sto, m.bf.sto}
+}
+
+// SetState restores the model state returned by State()
+func (m *Quinn) SetState(x []float64) error {
+	if err := checkState("Quinn", x, 5); err != nil {
+		return err
+	}
+	m.intc.sto, m.imp.sto, m.sz.sto, m.grav.sto, m.bf.sto = x[0], x[1], x[2], x[3], x[4]
+	return nil
+}
+
+// StateBounds returns the bounds of the state variables returned by State()
+func (m *Quinn) StateBounds() (lo, hi []float64) {
+	return storages(m.intc.cap, m.imp.cap, m.sz.cap, m.grav.cap, unbounded)
+}
@@ -0,0 +1,159 @@
+package regionalize
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	rr "github.com/maseology/goHydro/rainrun"
+	"github.com/maseology/goHydro/rainrun/optimize"
+	"github.com/maseology/mmio"
+)
+
+// CrossValidation holds the leave-one-out scores of each transfer method
+type CrossValidation struct {
+	Objective string
+	Methods   []string
+	Donors    []string
+	F         [][]float64 // [method][donor] objective function value of the transferred parameters
+	Fcal      []float64   // objective function value of each donor's calibrated parameters (benchmark)
+}
+
+// LeaveOneOut removes each donor in turn, predicts its parameters from the
+// remaining donors using every method and scores the transferred parameters
+// against its observed flows following warmup timesteps. Donors require forcing data.
+func (r *Regionalizer) LeaveOneOut(warmup int, obj optimize.Objective, methods ...Method) (*CrossValidation, error) {
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("regionalize.LeaveOneOut: no methods given")
+	}
+	for _, d := range r.Donors {
+		if d.Frc == nil {
+			return nil, fmt.Errorf("regionalize.LeaveOneOut: donor %s has no forcing data", d.Name)
+		}
+		if warmup >= len(d.Frc.D)-1 {
+			return nil, fmt.Errorf("regionalize.LeaveOneOut: warm-up of %d exceeds the %d timesteps of donor %s", warmup, len(d.Frc.D), d.Name)
+		}
+	}
+	cv := CrossValidation{
+		Objective: obj.Name,
+		Methods:   make([]string, len(methods)),
+		Donors:    make([]string, len(r.Donors)),
+		F:         make([][]float64, len(methods)),
+		Fcal:      make([]float64, len(r.Donors)),
+	}
+	for m, mt := range methods {
+		cv.Methods[m] = mt.Name
+		cv.F[m] = make([]float64, len(r.Donors))
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(r.Donors))
+	for i, d := range r.Donors {
+		cv.Donors[i] = d.Name
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			others := make([]int, 0, len(r.Donors)-1)
+			for j := range r.Donors {
+				if j != i {
+					others = append(others, j)
+				}
+			}
+			f, err := r.score(d, d.P, warmup, obj)
+			if err != nil {
+				errs <- err
+				return
+			}
+			cv.Fcal[i] = f
+			for m, mt := range methods {
+				p, err := mt.predict(r, others, d.Attr)
+				if err != nil {
+					errs <- fmt.Errorf("regionalize.LeaveOneOut: donor %s: %v", d.Name, err)
+					return
+				}
+				if cv.F[m][i], err = r.score(d, p, warmup, obj); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+	return &cv, nil
+}
+
+func (r *Regionalizer) score(d Donor, p []float64, warmup int, obj optimize.Objective) (float64, error) {
+	m, err := rr.NewModelAt(r.Model, d.Frc.Timestep, p...)
+	if err != nil {
+		return math.NaN(), fmt.Errorf("regionalize: donor %s: %v", d.Name, err)
+	}
+	o, s := make([]float64, len(d.Frc.D)), make([]float64, len(d.Frc.D))
+	for t, v := range d.Frc.D {
+		_, _, q, _ := m.Update(&v)
+		o[t], s[t] = v.Q, q
+	}
+	oo, ss := rr.Masked(o[warmup:], s[warmup:])
+	if len(oo) < 2 {
+		return math.NaN(), fmt.Errorf("regionalize: donor %s: fewer than 2 observed timesteps following the warm-up", d.Name)
+	}
+	return obj.F(oo, ss), nil
+}
+
+// String summarizes the transferability of each method: the mean and median
+// objective function value of the transferred parameters and the mean loss
+// relative to the calibrated parameters.
+func (cv *CrossValidation) String() string {
+	s := fmt.Sprintf("leave-one-out cross-validation of %d donors (%s)\n", len(cv.Donors), cv.Objective)
+	s += fmt.Sprintf("%16s %10s %10s %10s\n", "method", "mean", "median", "loss")
+	line := func(name string, f []float64) {
+		mn, md, ls := stats(f, cv.Fcal)
+		s += fmt.Sprintf("%16s %10.4f %10.4f %10.4f\n", name, mn, md, ls)
+	}
+	line("calibrated", cv.Fcal)
+	for m, name := range cv.Methods {
+		line(name, cv.F[m])
+	}
+	return s
+}
+
+func stats(f, fcal []float64) (mean, median, loss float64) {
+	n := float64(len(f))
+	c := append([]float64(nil), f...)
+	sort.Float64s(c)
+	if len(c)%2 == 0 {
+		median = (c[len(c)/2-1] + c[len(c)/2]) / 2.
+	} else {
+		median = c[len(c)/2]
+	}
+	for i, v := range f {
+		mean += v / n
+		loss += (v - fcal[i]) / n
+	}
+	return
+}
+
+// WriteCSV saves the objective function values of each donor and method
+func (cv *CrossValidation) WriteCSV(fp string) {
+	col := func(v []float64) []interface{} {
+		c := make([]interface{}, len(v))
+		for i, x := range v {
+			c[i] = x
+		}
+		return c
+	}
+	names := make([]interface{}, len(cv.Donors))
+	for i, d := range cv.Donors {
+		names[i] = d
+	}
+	hdr, cols := "donor,calibrated", [][]interface{}{names, col(cv.Fcal)}
+	for m, name := range cv.Methods {
+		hdr += "," + name
+		cols = append(cols, col(cv.F[m]))
+	}
+	mmio.WriteCSV(fp, hdr, cols...)
+}
@@ -0,0 +1,309 @@
+package regionalize
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	rr "github.com/maseology/goHydro/rainrun"
+)
+
+// Donor is a gauged catchment of calibrated parameters
+type Donor struct {
+	Name string
+	Frc  *rr.Frc   // forcing data, used in cross-validation (optional)
+	Attr []float64 // catchment attributes, defaults to Frc.Loc
+	P    []float64 // calibrated parameters
+}
+
+// Regionalizer transfers the parameters of a set of gauged donor catchments
+// to ungauged catchments. Parameters are transferred as given: donors and
+// target catchments are to share a timestep.
+type Regionalizer struct {
+	Model    string
+	Donors   []Donor
+	Timestep float64 // [s], taken from the donor forcings (default: daily)
+	mi       *rr.ModelInfo
+	nattr    int
+}
+
+// New Regionalizer constructor
+func New(model string, donors ...Donor) (*Regionalizer, error) {
+	mi, ok := rr.Lookup(model)
+	if !ok {
+		return nil, fmt.Errorf("regionalize.New: unrecognized model: %s", model)
+	}
+	if len(donors) < 2 {
+		return nil, fmt.Errorf("regionalize.New: at least 2 donors required, %d given", len(donors))
+	}
+	r := Regionalizer{Model: model, Donors: make([]Donor, len(donors)), mi: mi, nattr: -1}
+	for i, d := range donors {
+		if d.Frc != nil {
+			if d.Attr == nil {
+				d.Attr = d.Frc.Loc
+			}
+			if ts := d.Frc.Timestep; ts > 0. {
+				if r.Timestep > 0. && ts != r.Timestep {
+					return nil, fmt.Errorf("regionalize.New: donor %s: timestep %.0fs differs from %.0fs", d.Name, ts, r.Timestep)
+				}
+				r.Timestep = ts
+			}
+		}
+		if len(d.P) != mi.Ndim() {
+			return nil, fmt.Errorf("regionalize.New: donor %s: %d parameters given, %d expected", d.Name, len(d.P), mi.Ndim())
+		}
+		if r.nattr < 0 {
+			r.nattr = len(d.Attr)
+		} else if len(d.Attr) != r.nattr {
+			return nil, fmt.Errorf("regionalize.New: donor %s: %d attributes given, %d expected", d.Name, len(d.Attr), r.nattr)
+		}
+		r.Donors[i] = d
+	}
+	if r.Timestep <= 0. {
+		r.Timestep = 86400.
+	}
+	return &r, nil
+}
+
+// Method is a parameter transfer method
+type Method struct {
+	Name    string
+	predict func(r *Regionalizer, donors []int, attr []float64) ([]float64, error)
+}
+
+// Predict returns the parameters of an ungauged catchment of attributes attr
+func (r *Regionalizer) Predict(m Method, attr []float64) ([]float64, error) {
+	if len(attr) != r.nattr {
+		return nil, fmt.Errorf("regionalize.Predict: %d attributes given, %d expected", len(attr), r.nattr)
+	}
+	ids := make([]int, len(r.Donors))
+	for i := range ids {
+		ids[i] = i
+	}
+	return m.predict(r, ids, attr)
+}
+
+// NearestNeighbour transfers the parameters of the k closest donors, where
+// distance is computed from the coordinate attributes (e.g., projected
+// easting and northing) of indices coords. When k > 1, parameters are
+// averaged over sample space weighted by inverse distance.
+func NearestNeighbour(coords []int, k int) Method {
+	return Method{fmt.Sprintf("nearest%d", k), func(r *Regionalizer, donors []int, attr []float64) ([]float64, error) {
+		if err := r.check(coords); err != nil {
+			return nil, err
+		}
+		d := make([]float64, len(donors))
+		for i, id := range donors {
+			for _, j := range coords {
+				d[i] += (r.Donors[id].Attr[j] - attr[j]) * (r.Donors[id].Attr[j] - attr[j])
+			}
+			d[i] = math.Sqrt(d[i])
+		}
+		return r.closest(donors, d, k), nil
+	}}
+}
+
+// Similarity transfers the parameters of the k donors most physically
+// similar (Burn and Boorman, 1993): the Euclidean distance of attributes
+// attrs standardized by their mean and standard deviation among donors.
+// When k > 1, parameters are averaged over sample space weighted by inverse distance.
+func Similarity(attrs []int, k int) Method {
+	return Method{fmt.Sprintf("similar%d", k), func(r *Regionalizer, donors []int, attr []float64) ([]float64, error) {
+		if err := r.check(attrs); err != nil {
+			return nil, err
+		}
+		_, sd := r.moments(donors, attrs)
+		d := make([]float64, len(donors))
+		for i, id := range donors {
+			for jj, j := range attrs {
+				dz := (r.Donors[id].Attr[j] - attr[j]) / sd[jj]
+				d[i] += dz * dz
+			}
+			d[i] = math.Sqrt(d[i])
+		}
+		return r.closest(donors, d, k), nil
+	}}
+}
+
+// Regression predicts each parameter from a multiple linear regression on
+// attributes attrs, fit over sample space (i.e., parameters scaled [0,1]
+// within their bounds, log-scaled where so sampled) and clamped to the bounds.
+func Regression(attrs []int) Method {
+	return Method{"regression", func(r *Regionalizer, donors []int, attr []float64) ([]float64, error) {
+		if err := r.check(attrs); err != nil {
+			return nil, err
+		}
+		nx := len(attrs) + 1
+		if len(donors) <= nx {
+			return nil, fmt.Errorf("regionalize.Regression: %d donors insufficient to fit %d coefficients", len(donors), nx)
+		}
+		mn, sd := r.moments(donors, attrs)
+		z := func(a []float64) []float64 {
+			x := make([]float64, nx)
+			x[0] = 1.
+			for jj, j := range attrs {
+				x[jj+1] = (a[j] - mn[jj]) / sd[jj]
+			}
+			return x
+		}
+
+		// normal equations
+		xtx, x0 := make([][]float64, nx), z(attr)
+		for i := range xtx {
+			xtx[i] = make([]float64, nx)
+		}
+		xs := make([][]float64, len(donors))
+		for i, id := range donors {
+			xs[i] = z(r.Donors[id].Attr)
+			for a := range nx {
+				for b := range nx {
+					xtx[a][b] += xs[i][a] * xs[i][b]
+				}
+			}
+		}
+		p := make([]float64, r.mi.Ndim())
+		for k := range p {
+			xty := make([]float64, nx)
+			for i, id := range donors {
+				u := r.toU(k, r.Donors[id].P[k])
+				for a := range nx {
+					xty[a] += xs[i][a] * u
+				}
+			}
+			b, err := solve(xtx, xty)
+			if err != nil {
+				return nil, fmt.Errorf("regionalize.Regression: %s: %v", r.mi.Params[k].Name, err)
+			}
+			u := 0.
+			for a := range nx {
+				u += b[a] * x0[a]
+			}
+			p[k] = r.fromU(k, math.Min(math.Max(u, 0.), 1.))
+		}
+		return p, nil
+	}}
+}
+
+func (r *Regionalizer) check(attrs []int) error {
+	if len(attrs) == 0 {
+		return fmt.Errorf("regionalize: no attributes selected")
+	}
+	for _, j := range attrs {
+		if j < 0 || j >= r.nattr {
+			return fmt.Errorf("regionalize: attribute %d out of range (%d attributes)", j, r.nattr)
+		}
+	}
+	return nil
+}
+
+// moments returns the mean and standard deviation of attributes among donors
+func (r *Regionalizer) moments(donors []int, attrs []int) (mn, sd []float64) {
+	mn, sd = make([]float64, len(attrs)), make([]float64, len(attrs))
+	n := float64(len(donors))
+	for jj, j := range attrs {
+		for _, id := range donors {
+			mn[jj] += r.Donors[id].Attr[j] / n
+		}
+		for _, id := range donors {
+			sd[jj] += (r.Donors[id].Attr[j] - mn[jj]) * (r.Donors[id].Attr[j] - mn[jj]) / n
+		}
+		sd[jj] = math.Sqrt(sd[jj])
+		if sd[jj] == 0. {
+			sd[jj] = 1. // uniform attribute, no discrimination
+		}
+	}
+	return
+}
+
+// closest returns the inverse-distance weighted parameters of the k nearest donors
+func (r *Regionalizer) closest(donors []int, d []float64, k int) []float64 {
+	const eps = 1e-12
+	k = min(max(k, 1), len(donors))
+	ii := make([]int, len(donors))
+	for i := range ii {
+		ii[i] = i
+	}
+	sort.SliceStable(ii, func(a, b int) bool { return d[ii[a]] < d[ii[b]] })
+	if k == 1 {
+		return append([]float64(nil), r.Donors[donors[ii[0]]].P...)
+	}
+	u, sw := make([]float64, r.mi.Ndim()), 0.
+	for _, i := range ii[:k] {
+		w := 1. / math.Max(d[i], eps)
+		for j := range u {
+			u[j] += w * r.toU(j, r.Donors[donors[i]].P[j])
+		}
+		sw += w
+	}
+	p := make([]float64, len(u))
+	for j := range u {
+		p[j] = r.fromU(j, u[j]/sw)
+	}
+	return p
+}
+
+// bounds returns the bounds of parameter k at the donor timestep
+func (r *Regionalizer) bounds(k int) (lo, hi float64, lg bool) {
+	pp := r.mi.Params[k]
+	lo, hi = pp.AtTimestep(pp.Lower, r.Timestep), pp.AtTimestep(pp.Upper, r.Timestep)
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	return lo, hi, pp.Log && lo > 0.
+}
+
+// toU scales parameter k to sample space [0,1]
+func (r *Regionalizer) toU(k int, v float64) float64 {
+	lo, hi, lg := r.bounds(k)
+	if hi == lo {
+		return 0.
+	}
+	if lg {
+		return (math.Log(v) - math.Log(lo)) / (math.Log(hi) - math.Log(lo))
+	}
+	return (v - lo) / (hi - lo)
+}
+
+func (r *Regionalizer) fromU(k int, u float64) float64 {
+	lo, hi, lg := r.bounds(k)
+	if lg {
+		return math.Exp(math.Log(lo) + u*(math.Log(hi)-math.Log(lo)))
+	}
+	return lo + u*(hi-lo)
+}
+
+// solve Ax=b by Gaussian elimination with partial pivoting
+func solve(a [][]float64, b []float64) ([]float64, error) {
+	n := len(b)
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = append(append([]float64(nil), a[i]...), b[i])
+	}
+	for c := range n {
+		p := c
+		for i := c + 1; i < n; i++ {
+			if math.Abs(m[i][c]) > math.Abs(m[p][c]) {
+				p = i
+			}
+		}
+		if math.Abs(m[p][c]) < 1e-12 {
+			return nil, fmt.Errorf("singular system (collinear attributes)")
+		}
+		m[c], m[p] = m[p], m[c]
+		for i := c + 1; i < n; i++ {
+			f := m[i][c] / m[c][c]
+			for j := c; j <= n; j++ {
+				m[i][j] -= f * m[c][j]
+			}
+		}
+	}
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		x[i] = m[i][n]
+		for j := i + 1; j < n; j++ {
+			x[i] -= m[i][j] * x[j]
+		}
+		x[i] /= m[i][i]
+	}
+	return x, nil
+}
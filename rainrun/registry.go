@@ -0,0 +1,237 @@
+package rainrun
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Scaling describes how a (daily) parameter value is converted to the model timestep
+type Scaling int
+
+const (
+	Fixed     Scaling = iota // independent of timestep
+	PerDay                   // a rate given per day (e.g., mm/d), scaled linearly
+	Recession                // fraction drained per day: 1-(1-k)^(ts/86400)
+	Retention                // fraction retained per day: k^(ts/86400)
+	Days                     // a duration given in days, converted to timesteps
+)
+
+// Param describes a single model parameter
+type Param struct {
+	Name, Unit, Desc string
+	Lower, Upper     float64 // parameter bounds
+	Default          float64
+	Log              bool    // sampled over log space
+	Rate             bool    // given per second; scaled by Frc.Timestep (daily when unset) before being passed to New()
+	Step             Scaling // given at a daily timestep; converted to Frc.Timestep before being passed to New()
+}
+
+// AtTimestep converts parameter value v to timestep ts [s]; ts <= 0 is taken as daily
+func (p *Param) AtTimestep(v, ts float64) float64 {
+	f := 1.
+	if ts > 0. {
+		f = ts / 86400.
+	}
+	if p.Rate {
+		return v * f * 86400.
+	}
+	switch p.Step {
+	case PerDay:
+		return v * f
+	case Recession:
+		return 1. - math.Pow(1.-v, f)
+	case Retention:
+		return math.Pow(v, f)
+	case Days:
+		return v / f
+	}
+	return v
+}
+
+// Timestepper is implemented by models whose formulation depends on the
+// timestep (beyond the scaling of their parameters)
+type Timestepper interface {
+	SetTimestep(ts float64) // timestep [s]
+}
+
+// ModelInfo holds the metadata of a registered Lumper, optionally
+// coupled to a snowpack model and PET estimator (e.g., "GR4J+CCF+Makkink")
+type ModelInfo struct {
+	Name          string
+	Params        []Param
+	NeedsTimestep bool          // model parameterization depends on Frc.Timestep
+	New           func() Lumper // returns an un-parameterized model
+	Snow          *SnowInfo     // coupled snowpack model, nil when yield is taken from forcing
+	PET           *PETInfo      // coupled PET estimator, nil when PET is taken from forcing
+}
+
+// Ndim returns the number of model parameters
+func (mi *ModelInfo) Ndim() int { return len(mi.Params) }
+
+// ParamNames returns the ordered set of parameter names
+func (mi *ModelInfo) ParamNames() []string {
+	s := make([]string, len(mi.Params))
+	for i, p := range mi.Params {
+		s[i] = p.Name
+	}
+	return s
+}
+
+// Defaults returns the default parameter set, converted to timestep ts [s]
+func (mi *ModelInfo) Defaults(ts float64) []float64 {
+	p := make([]float64, len(mi.Params))
+	for i, pp := range mi.Params {
+		p[i] = pp.AtTimestep(pp.Default, ts)
+	}
+	return p
+}
+
+// Parts returns the number of Lumper, snowpack and PET parameters, respectively
+func (mi *ModelInfo) Parts() (nl, ns, ne int) {
+	if mi.Snow != nil {
+		ns = len(mi.Snow.Params)
+	}
+	if mi.PET != nil {
+		ne = len(mi.PET.Params)
+	}
+	nl = len(mi.Params) - ns - ne
+	return
+}
+
+// Build returns a new parameterized model; returns an error when the parameter
+// count is wrong or the parameters are rejected by the model (input errors)
+func (mi *ModelInfo) Build(p ...float64) (m Model, err error) {
+	if len(p) != len(mi.Params) {
+		return nil, fmt.Errorf("rainrun.%s: %d parameters given, %d expected", mi.Name, len(p), len(mi.Params))
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			m, err = nil, fmt.Errorf("rainrun.%s: %v, parameters: %g", mi.Name, r, p)
+		}
+	}()
+	nl, ns, ne := mi.Parts()
+	c := &Coupled{L: mi.New(), nm: mi.Name, n: []int{nl, ns, ne}}
+	if mi.Snow != nil {
+		c.SP = mi.Snow.New()
+	}
+	if mi.PET != nil {
+		c.ET = mi.PET.New()
+	}
+	c.New(p...)
+	return c, nil
+}
+
+// BuildFor returns a new parameterized model set to run at timestep ts [s]
+func (mi *ModelInfo) BuildFor(ts float64, p ...float64) (Model, error) {
+	m, err := mi.Build(p...)
+	if err != nil {
+		return nil, err
+	}
+	if ts > 0. {
+		m.(*Coupled).SetTimestep(ts)
+	}
+	return m, nil
+}
+
+var registry = func() map[string]*ModelInfo {
+	r := make(map[string]*ModelInfo, len(builtin))
+	for _, mi := range builtin {
+		add(r, mi)
+	}
+	return r
+}()
+
+var snowpacks = func() map[string]*SnowInfo {
+	r := make(map[string]*SnowInfo, len(builtinSnow))
+	for i := range builtinSnow {
+		r[builtinSnow[i].Name] = &builtinSnow[i]
+	}
+	return r
+}()
+
+var pets = func() map[string]*PETInfo {
+	r := make(map[string]*PETInfo, len(builtinPET))
+	for i := range builtinPET {
+		r[builtinPET[i].Name] = &builtinPET[i]
+	}
+	return r
+}()
+
+func add(r map[string]*ModelInfo, mi ModelInfo) {
+	if mi.New == nil {
+		panic("rainrun.Register: model constructor required")
+	}
+	for _, p := range mi.Params {
+		if p.Rate {
+			mi.NeedsTimestep = true
+		}
+	}
+	r[mi.Name] = &mi
+}
+
+// Register adds a model to the registry, replacing any model of the same name
+func Register(mi ModelInfo) { add(registry, mi) }
+
+// RegisterSnow adds a snowpack model to the registry, replacing any model of the same name
+func RegisterSnow(si SnowInfo) { snowpacks[si.Name] = &si }
+
+// RegisterPET adds a PET estimator to the registry, replacing any estimator of the same name
+func RegisterPET(pi PETInfo) { pets[pi.Name] = &pi }
+
+// Lookup returns the metadata of a registered model. Lumpers are coupled to
+// registered snowpack models and/or PET estimators by joining names with "+",
+// for example "GR4J+CCF+Makkink" or "HBV+DDF".
+func Lookup(name string) (*ModelInfo, bool) {
+	sp := strings.Split(name, "+")
+	mi, ok := registry[sp[0]]
+	if !ok || len(sp) == 1 {
+		return mi, ok
+	}
+	c := *mi
+	c.Name = name
+	c.Params = append([]Param(nil), mi.Params...)
+	for _, s := range sp[1:] {
+		if si, ok := snowpacks[s]; ok && c.Snow == nil && c.PET == nil {
+			c.Snow = si
+			c.Params = append(c.Params, si.Params...)
+		} else if pi, ok := pets[s]; ok && c.PET == nil {
+			c.PET = pi
+			c.Params = append(c.Params, pi.Params...)
+		} else {
+			return nil, false
+		}
+	}
+	return &c, true
+}
+
+// Models returns the sorted names of all registered models
+func Models() []string { return keys(registry) }
+
+// Snowpacks returns the sorted names of all registered snowpack models
+func Snowpacks() []string { return keys(snowpacks) }
+
+// PETs returns the sorted names of all registered PET estimators
+func PETs() []string { return keys(pets) }
+
+func keys[T any](m map[string]T) []string {
+	s := make([]string, 0, len(m))
+	for k := range m {
+		s = append(s, k)
+	}
+	sort.Strings(s)
+	return s
+}
+
+// NewModel builds a registered model by name
+func NewModel(name string, p ...float64) (Model, error) { return NewModelAt(name, 0., p...) }
+
+// NewModelAt builds a registered model by name, set to run at timestep ts [s]
+func NewModelAt(name string, ts float64, p ...float64) (Model, error) {
+	mi, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("rainrun: unrecognized model: %s", name)
+	}
+	return mi.BuildFor(ts, p...)
+}
@@ -0,0 +1,206 @@
+package rainrun
+
+import "math"
+
+// SACSMA Sacramento Soil Moisture Accounting model
+// ref: Burnash, R.J.C., 1995. The NWS River Forecast System - catchment modeling. In: Singh, V.P. (Ed.), Computer Models of Watershed Hydrology. Water Resources Publications, Littleton, Colorado. pp. 311-366.
+// riparian vegetation (RIVA) is neglected and the fraction of lower zone free water unavailable for transpiration (RSERV) is fixed
+type SACSMA struct {
+	uztwc, uzfwc, lztwc, lzfsc, lzfpc, adimc     float64 // contents
+	uztwm, uzfwm, uzk, pctim, adimp, zperc, rexp float64
+	lztwm, lzfsm, lzfpm, lzsk, lzpk, pfree, side float64
+}
+
+const rserv = .3
+
+// New SACSMA constructor
+// [uztwm, uzfwm, uzk, pctim, adimp, zperc, rexp, lztwm, lzfsm, lzfpm, lzsk, lzpk, pfree, side]
+func (m *SACSMA) New(p ...float64) {
+	if p[0] <= 0. || p[1] <= 0. || p[7] <= 0. || p[8] <= 0. || p[9] <= 0. || fracCheck(p[2]) || fracCheck(p[3]) || fracCheck(p[4]) || p[3]+p[4] > 1. || fracCheck(p[10]) || fracCheck(p[11]) || fracCheck(p[12]) || p[13] < 0. {
+		panic("SACSMA input error")
+	}
+	m.uztwm = p[0]  // upper zone tension water capacity
+	m.uzfwm = p[1]  // upper zone free water capacity
+	m.uzk = p[2]    // upper zone free water lateral depletion rate (interflow)
+	m.pctim = p[3]  // permanently impervious fraction
+	m.adimp = p[4]  // additional (variable) impervious fraction
+	m.zperc = p[5]  // maximum percolation rate coefficient
+	m.rexp = p[6]   // percolation equation exponent
+	m.lztwm = p[7]  // lower zone tension water capacity
+	m.lzfsm = p[8]  // lower zone supplemental free water capacity
+	m.lzfpm = p[9]  // lower zone primary free water capacity
+	m.lzsk = p[10]  // lower zone supplemental free water depletion rate
+	m.lzpk = p[11]  // lower zone primary free water depletion rate
+	m.pfree = p[12] // fraction of percolation going directly to lower zone free water
+	m.side = p[13]  // ratio of non-channel baseflow (deep recharge) to channel baseflow
+}
+
+// Update state for daily inputs
+func (m *SACSMA) Update(p, ep float64) (float64, float64, float64) {
+	parea := 1. - m.pctim - m.adimp
+
+	// evapotranspiration from the upper zone
+	e1 := math.Min(m.uztwc, ep*m.uztwc/m.uztwm)
+	m.uztwc -= e1
+	red := ep - e1
+	e2 := 0.
+	if m.uztwc <= 0. {
+		e2 = math.Min(red, m.uzfwc)
+		m.uzfwc -= e2
+		red -= e2
+	}
+	if m.uztwc/m.uztwm < m.uzfwc/m.uzfwm { // upper zone free water replenishes tension water
+		uzrat := (m.uztwc + m.uzfwc) / (m.uztwm + m.uzfwm)
+		m.uztwc = m.uztwm * uzrat
+		m.uzfwc = m.uzfwm * uzrat
+	}
+
+	// evapotranspiration from the lower zone
+	e3 := math.Min(m.lztwc, red*m.lztwc/(m.uztwm+m.lztwm))
+	m.lztwc -= e3
+	saved := rserv * (m.lzfpm + m.lzfsm)
+	ratlzt := m.lztwc / m.lztwm
+	ratlz := (m.lztwc + m.lzfpc + m.lzfsc - saved) / (m.lztwm + m.lzfpm + m.lzfsm - saved)
+	if ratlzt < ratlz { // lower zone free water replenishes tension water
+		del := (ratlz - ratlzt) * m.lztwm
+		m.lztwc += del
+		m.lzfsc -= del
+		if m.lzfsc < 0. {
+			m.lzfpc += m.lzfsc
+			m.lzfsc = 0.
+		}
+	}
+
+	// evapotranspiration from the additional impervious area
+	e5 := e1 + (red+e2)*(m.adimc-e1-m.uztwc)/(m.uztwm+m.lztwm)
+	e5 = math.Max(0., math.Min(e5, m.adimc))
+	m.adimc -= e5
+	e5 *= m.adimp
+
+	// rainfall in excess of upper zone tension water
+	twx := p + m.uztwc - m.uztwm
+	if twx < 0. {
+		m.uztwc += p
+		twx = 0.
+	} else {
+		m.uztwc = m.uztwm
+	}
+	m.adimc += p - twx
+	roimp := p * m.pctim // runoff from the permanently impervious area
+
+	// percolation and runoff computed over increments of no more than 5mm
+	ninc := math.Floor(1. + .2*(m.uzfwc+twx))
+	dinc := 1. / ninc
+	pinc := twx / ninc
+	duz := 1. - math.Pow(1.-m.uzk, dinc)
+	dlzp := 1. - math.Pow(1.-m.lzpk, dinc)
+	dlzs := 1. - math.Pow(1.-m.lzsk, dinc)
+	sbf, ssur, sif, sperc, sdro := 0., 0., 0., 0., 0.
+	for range int(ninc) {
+		adsur := 0.
+		ratio := math.Max(0., (m.adimc-m.uztwc)/m.lztwm)
+		addro := pinc * ratio * ratio // direct runoff from the additional impervious area
+
+		// baseflow
+		bf := m.lzfpc * dlzp
+		m.lzfpc -= bf
+		sbf += bf
+		bf = m.lzfsc * dlzs
+		m.lzfsc -= bf
+		sbf += bf
+
+		if m.uzfwc > 0. {
+			// percolation
+			defr := 1. - (m.lztwc+m.lzfpc+m.lzfsc)/(m.lztwm+m.lzfpm+m.lzfsm)
+			perc := (m.lzfpm*dlzp + m.lzfsm*dlzs) * m.uzfwc / m.uzfwm * (1. + m.zperc*math.Pow(math.Max(0., defr), m.rexp))
+			perc = math.Min(perc, m.uzfwc)
+			if chk := m.lztwc + m.lzfpc + m.lzfsc + perc - m.lztwm - m.lzfpm - m.lzfsm; chk > 0. {
+				perc -= chk
+			}
+			m.uzfwc -= perc
+			sperc += perc
+
+			// interflow
+			del := m.uzfwc * duz
+			sif += del
+			m.uzfwc -= del
+
+			// distribute percolated water among the lower zones
+			percf := perc * m.pfree
+			perct := perc - percf
+			if perct+m.lztwc <= m.lztwm {
+				m.lztwc += perct
+			} else {
+				percf += perct + m.lztwc - m.lztwm
+				m.lztwc = m.lztwm
+			}
+			if percf > 0. {
+				hpl := m.lzfpm / (m.lzfpm + m.lzfsm)
+				ratlp, ratls := m.lzfpc/m.lzfpm, m.lzfsc/m.lzfsm
+				fracp := 1.
+				if d := (1. - ratlp) + (1. - ratls); d > 0. {
+					fracp = math.Min(1., hpl*2.*(1.-ratlp)/d)
+				}
+				percs := percf * (1. - fracp)
+				m.lzfsc += percs
+				if m.lzfsc > m.lzfsm {
+					percs -= m.lzfsc - m.lzfsm
+					m.lzfsc = m.lzfsm
+				}
+				m.lzfpc += percf - percs
+				if m.lzfpc > m.lzfpm {
+					m.lztwc += m.lzfpc - m.lzfpm
+					m.lzfpc = m.lzfpm
+				}
+			}
+		}
+
+		// surface runoff
+		if pinc > 0. {
+			if pinc+m.uzfwc <= m.uzfwm {
+				m.uzfwc += pinc
+			} else {
+				sur := pinc + m.uzfwc - m.uzfwm
+				m.uzfwc = m.uzfwm
+				ssur += sur * parea
+				adsur = sur * (1. - addro/pinc)
+				ssur += adsur * m.adimp
+			}
+		}
+		m.adimc += pinc - addro - adsur
+		if m.adimc > m.uztwm+m.lztwm {
+			addro += m.adimc - (m.uztwm + m.lztwm)
+			m.adimc = m.uztwm + m.lztwm
+		}
+		sdro += addro * m.adimp
+	}
+
+	sif *= parea
+	bfcc := sbf * parea / (1. + m.side) // channel baseflow; the remainder is lost to deep recharge
+	a := (e1+e2+e3)*parea + e5
+	return a, roimp + sdro + ssur + sif + bfcc, sperc * parea
+}
+
+// Storage returns total storage
+func (m *SACSMA) Storage() float64 {
+	return (m.uztwc+m.uzfwc+m.lztwc+m.lzfsc+m.lzfpc)*(1.-m.pctim-m.adimp) + m.adimc*m.adimp
+}
+
+// State returns the model state: [uztwc, uzfwc, lztwc, lzfsc, lzfpc, adimc]
+func (m *SACSMA) State() []float64 {
+	return []float64{m.uztwc, m.uzfwc, m.lztwc, m.lzfsc, m.lzfpc, m.adimc}
+}
+
+// SetState restores the model state returned by State()
+func (m *SACSMA) SetState(x []float64) error {
+	if err := checkState("SACSMA", x, 6); err != nil {
+		return err
+	}
+	m.uztwc, m.uzfwc, m.lztwc, m.lzfsc, m.lzfpc, m.adimc = x[0], x[1], x[2], x[3], x[4], x[5]
+	return nil
+}
+
+// StateBounds returns the bounds of the state variables returned by State()
+func (m *SACSMA) StateBounds() (lo, hi []float64) {
+	return storages(m.uztwm, m.uzfwm, m.lztwm, m.lzfsm, m.lzfpm, m.uztwm+m.lztwm)
+}
@@ -1,41 +1,68 @@
 package sample
 
 import (
+	"fmt"
 	"log"
 	"math"
-	"math/rand"
 	"runtime"
-	"time"
+	"sync"
 
-	"github.com/maseology/UTM"
+	"github.com/maseology/goHydro/internal/rngseed"
 	rr "github.com/maseology/goHydro/rainrun"
-	"github.com/maseology/goHydro/solirrad"
-	mrg63k3a "github.com/maseology/goRNG/MRG63k3a"
-	"github.com/maseology/montecarlo"
 )
 
-// Sample samples a rainrun model
+// Sample samples the GR4J model coupled to the CCF snowpack model and Makkink PET, seeded from the clock
+//
+// Deprecated: use SampleModel(frc, "GR4J+CCF+Makkink", nsmpl, fitness).
 func Sample(frc rr.Frc, nsmpl int, fitness func(o, s []float64) float64) ([][]float64, []float64) {
-
-	lat, _, err := UTM.ToLatLon(frc.Loc[1], frc.Loc[2], 17, "", true)
+	u, f, err := SampleModel(frc, "GR4J+CCF+Makkink", nsmpl, fitness)
 	if err != nil {
 		log.Fatalf("%v", err)
 	}
-	si := solirrad.New(lat, math.Tan(frc.Loc[4]), frc.Loc[5])
+	return u, f
+}
+
+// SampleModel samples a registered rainrun model (see rainrun.Models()),
+// including coupled models (e.g., "GR4J+CCF+Makkink"), seeded from the clock
+// with the seed logged (pass it to SampleSeed to repeat a sample set);
+// scores follow a 1-year warm-up
+func SampleModel(frc rr.Frc, mdl string, nsmpl int, fitness func(o, s []float64) float64) ([][]float64, []float64, error) {
+	u, f, seed, err := SampleSeed(frc, mdl, nsmpl, frc.Year(), 0, fitness)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Printf("sample: %d samples of %s, seed: %d\n", nsmpl, mdl, seed)
+	return u, f, nil
+}
 
+// SampleSeed samples a registered rainrun model using seed, or the clock when
+// zero, scoring each sample following nwarm timesteps; returns the sample space
+// [0,1] points, their fitness and the seed used. A given seed reproduces the sample set.
+func SampleSeed(frc rr.Frc, mdl string, nsmpl, nwarm int, seed int64, fitness func(o, s []float64) float64) ([][]float64, []float64, int64, error) {
+	mi, ok := rr.Lookup(mdl)
+	if !ok {
+		return nil, nil, 0, fmt.Errorf("sample.SampleSeed: unrecognized model: %s", mdl)
+	}
+	smpl, err := Get(mdl)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if n := len(smpl(make([]float64, mi.Ndim()), frc.Timestep)); n != mi.Ndim() {
+		return nil, nil, 0, fmt.Errorf("sample.SampleSeed: %s sampler returns %d parameters, %d expected", mdl, n, mi.Ndim())
+	}
+	if nwarm < 0 || nwarm >= frc.Ndt {
+		return nil, nil, 0, fmt.Errorf("sample.SampleSeed: warm-up of %d timesteps given for a %d timestep record", nwarm, frc.Ndt)
+	}
 	obs := make([]float64, frc.Ndt)
 	for i, v := range frc.D {
 		obs[i] = v.Q // [m/d]??
 	}
 
-	rng := rand.New(mrg63k3a.New())
-	rng.Seed(time.Now().UnixNano())
-
-	ndim := 10
-	gen := func(u []float64, i int) float64 {
-		var m rr.MakkinkCCFGR4J
-		m.New(append(MakkinkCCFGR4J(u), frc.D[0].Q)...)
-		m.SI = &si
+	gen := func(u []float64) float64 {
+		m, err := mi.BuildFor(frc.Timestep, smpl(u, frc.Timestep)...)
+		if err != nil {
+			panic(err) // sampler and model dimensions are checked above
+		}
 
 		f := func(obs []float64) float64 {
 			sim := make([]float64, frc.Ndt)
@@ -43,7 +70,7 @@ func Sample(frc rr.Frc, nsmpl int, fitness func(o, s []float64) float64) ([][]fl
 				_, _, r, _ := m.Update(&v)
 				sim[i] = r
 			}
-			return fitness(obs[365:], sim[365:])
+			return fitness(rr.Masked(obs[nwarm:], sim[nwarm:]))
 		}(obs)
 		if math.IsNaN(f) {
 			// log.Fatalf("Objective function error, u: %v\n", u)
@@ -52,5 +79,32 @@ func Sample(frc rr.Frc, nsmpl int, fitness func(o, s []float64) float64) ([][]fl
 		return f
 	}
 
-	return montecarlo.GenerateSamples(gen, ndim, nsmpl, runtime.GOMAXPROCS(0))
+	// samples are drawn up front, so that the set is independent of evaluation order
+	rng, seed := rngseed.New(seed)
+	u := make([][]float64, nsmpl)
+	for i := range u {
+		u[i] = make([]float64, mi.Ndim())
+		for j := range u[i] {
+			u[i][j] = rng.Float64()
+		}
+	}
+
+	f := make([]float64, nsmpl)
+	var wg sync.WaitGroup
+	ch := make(chan int)
+	for range runtime.GOMAXPROCS(0) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range ch {
+				f[i] = gen(u[i])
+			}
+		}()
+	}
+	for i := range u {
+		ch <- i
+	}
+	close(ch)
+	wg.Wait()
+	return u, f, seed, nil
 }
@@ -239,3 +239,72 @@ func Tank(u []float64) []float64 {
 	b3 := mm.LinearTransform(0., 1., u[11])
 	return []float64{z11, z12, z2, z3, a11, a12, a2, a3, a4, b1, b2, b3}
 }
+
+// SACSMA (14)
+func SACSMA(u []float64) []float64 {
+	uztwm := mm.LinearTransform(1., 150., u[0])
+	uzfwm := mm.LinearTransform(1., 150., u[1])
+	uzk := mm.LinearTransform(.1, .5, u[2])
+	pctim := mm.LinearTransform(0., .1, u[3])
+	adimp := mm.LinearTransform(0., .4, u[4])
+	zperc := mm.LinearTransform(1., 250., u[5])
+	rexp := mm.LinearTransform(1., 5., u[6])
+	lztwm := mm.LinearTransform(1., 500., u[7])
+	lzfsm := mm.LinearTransform(1., 1000., u[8])
+	lzfpm := mm.LinearTransform(1., 1000., u[9])
+	lzsk := mm.LinearTransform(.01, .25, u[10])
+	lzpk := mm.LogLinearTransform(.0001, .025, u[11])
+	pfree := mm.LinearTransform(0., .6, u[12])
+	side := mm.LinearTransform(0., .5, u[13])
+	return []float64{uztwm, uzfwm, uzk, pctim, adimp, zperc, rexp, lztwm, lzfsm, lzfpm, lzsk, lzpk, pfree, side}
+}
+
+// HYMOD (5)
+func HYMOD(u []float64) []float64 {
+	cmax := mm.LinearTransform(1., soildepth, u[0])
+	bexp := mm.LinearTransform(0., 2., u[1])
+	alpha := mm.LinearTransform(0., 1., u[2])
+	kq := mm.LinearTransform(.1, 1., u[3])
+	ks := mm.LinearTransform(0., .1, u[4])
+	return []float64{cmax, bexp, alpha, kq, ks}
+}
+
+// IHACRES (6)
+func IHACRES(u []float64) []float64 {
+	f := mm.LinearTransform(.01, 3., u[0])
+	e := mm.LinearTransform(.1, 1.5, u[1])
+	d := mm.LinearTransform(50., 550., u[2])
+	tauq := mm.LinearTransform(.5, 10., u[3])
+	taus := mm.LogLinearTransform(10., 1000., u[4])
+	vs := mm.LinearTransform(0., 1., u[5])
+	return []float64{f, e, d, tauq, taus, vs}
+}
+
+// AWBM (9)
+func AWBM(u []float64) []float64 {
+	c1 := mm.LinearTransform(0., 50., u[0])
+	c2 := mm.LinearTransform(0., 200., u[1])
+	c3 := mm.LinearTransform(0., 500., u[2])
+	a := jointdist.SumToOne(u[3], u[4], u[5])
+	bfi := mm.LinearTransform(0., 1., u[6])
+	kb := mm.LinearTransform(.9, 1., u[7])
+	ks := mm.LinearTransform(0., 1., u[8])
+	return []float64{c1, c2, c3, a[0], a[1], a[2], bfi, kb, ks}
+}
+
+// Xinanjiang (12)
+func Xinanjiang(u []float64) []float64 {
+	wm := mm.LinearTransform(50., 500., u[0])
+	x := mm.LinearTransform(.05, .5, u[1])
+	y := mm.LinearTransform(.1, .9, u[2])
+	b := mm.LinearTransform(.05, 2., u[3])
+	c := mm.LinearTransform(0., .3, u[4])
+	im := mm.LinearTransform(0., .1, u[5])
+	sm := mm.LinearTransform(1., 100., u[6])
+	ex := mm.LinearTransform(.5, 2., u[7])
+	kig := mm.LinearTransform(.1, .9, u[8])
+	kif := mm.LinearTransform(0., 1., u[9])
+	ci := mm.LinearTransform(0., .99, u[10])
+	cg := mm.LinearTransform(.9, .999, u[11])
+	return []float64{wm, x, y, b, c, im, sm, ex, kig, kif, ci, cg}
+}
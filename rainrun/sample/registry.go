@@ -0,0 +1,109 @@
+package sample
+
+import (
+	"fmt"
+	"strings"
+
+	rr "github.com/maseology/goHydro/rainrun"
+	mm "github.com/maseology/mmaths"
+)
+
+// Sampler transforms a unit-hypercube sample u to a model parameter set; ts: timestep [s]
+type Sampler func(u []float64, ts float64) []float64
+
+// hand-coded parameter ranges, keyed by registered model name
+var samplers = map[string]Sampler{
+	"Atkinson":              func(u []float64, _ float64) []float64 { return Atkinson(u) },
+	"DawdyODonnell":         DawdyODonnell,
+	"GR4J":                  func(u []float64, _ float64) []float64 { return GR4J(u) },
+	"HBV":                   HBV,
+	"HMETS":                 func(u []float64, _ float64) []float64 { return HMETS(u) },
+	"ManabeGW":              func(u []float64, _ float64) []float64 { return ManabeGW(u) },
+	"MultiLayerCapacitance": func(u []float64, _ float64) []float64 { return MultiLayerCapacitance(u) },
+	"Quinn":                 func(u []float64, _ float64) []float64 { return Quinn(u) },
+	"SIXPAR":                func(u []float64, _ float64) []float64 { return SIXPAR(u) },
+	"SPLR":                  func(u []float64, _ float64) []float64 { return SPLR(u) },
+	"Tank":                  func(u []float64, _ float64) []float64 { return Tank(u) },
+	"SACSMA":                func(u []float64, _ float64) []float64 { return SACSMA(u) },
+	"HYMOD":                 func(u []float64, _ float64) []float64 { return HYMOD(u) },
+	"IHACRES":               func(u []float64, _ float64) []float64 { return IHACRES(u) },
+	"AWBM":                  func(u []float64, _ float64) []float64 { return AWBM(u) },
+	"Xinanjiang":            func(u []float64, _ float64) []float64 { return Xinanjiang(u) },
+}
+
+// hand-coded parameter ranges of snowpack models and PET estimators
+var components = map[string]Sampler{
+	"CCF":     func(u []float64, _ float64) []float64 { return CCF(u) },
+	"Makkink": func(u []float64, _ float64) []float64 { return Makkink(u) },
+}
+
+// Get returns the parameter sampler of a registered model, including
+// coupled models (e.g., "GR4J+CCF+Makkink"). Models without hand-coded
+// ranges are sampled from their registered bounds.
+func Get(name string) (Sampler, error) {
+	mi, ok := rr.Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("sample.Get: unrecognized model: %s", name)
+	}
+	nl, ns, _ := mi.Parts()
+	sl, ok := samplers[strings.Split(name, "+")[0]]
+	if ok {
+		sl = daily(sl, mi.Params[:nl])
+	} else {
+		sl = FromBounds(mi.Params[:nl])
+	}
+	if mi.Snow == nil && mi.PET == nil {
+		return sl, nil
+	}
+	ss, se := none, none
+	if mi.Snow != nil {
+		ss = component(mi.Snow.Name, mi.Snow.Params)
+	}
+	if mi.PET != nil {
+		se = component(mi.PET.Name, mi.PET.Params)
+	}
+	return func(u []float64, ts float64) []float64 {
+		p := sl(u[:nl], ts)
+		p = append(p, ss(u[nl:nl+ns], ts)...)
+		return append(p, se(u[nl+ns:], ts)...)
+	}, nil
+}
+
+func none([]float64, float64) []float64 { return nil }
+
+func component(name string, prms []rr.Param) Sampler {
+	if s, ok := components[name]; ok {
+		return daily(s, prms)
+	}
+	return FromBounds(prms)
+}
+
+// daily converts the timestep-dependent parameters of a hand-coded sampler,
+// given at a daily timestep, to timestep ts (rates are scaled by the sampler itself)
+func daily(s Sampler, prms []rr.Param) Sampler {
+	return func(u []float64, ts float64) []float64 {
+		p := s(u, ts)
+		for i, pp := range prms {
+			if !pp.Rate {
+				p[i] = pp.AtTimestep(p[i], ts)
+			}
+		}
+		return p
+	}
+}
+
+// FromBounds returns a sampler that transforms each dimension over the parameter bounds
+func FromBounds(prms []rr.Param) Sampler {
+	return func(u []float64, ts float64) []float64 {
+		p := make([]float64, len(prms))
+		for i, pp := range prms {
+			if pp.Log {
+				p[i] = mm.LogLinearTransform(pp.Lower, pp.Upper, u[i])
+			} else {
+				p[i] = mm.LinearTransform(pp.Lower, pp.Upper, u[i])
+			}
+			p[i] = pp.AtTimestep(p[i], ts)
+		}
+		return p
+	}
+}
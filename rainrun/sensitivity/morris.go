@@ -0,0 +1,113 @@
+package sensitivity
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/maseology/goHydro/internal/rngseed"
+)
+
+// MorrisResult holds the elementary effect statistics of each parameter
+type MorrisResult struct {
+	Params    []string
+	Mu, Sigma []float64
+	MuStar    []Index // mean of the absolute elementary effects
+	R         int     // number of trajectories; R(d+1) model evaluations
+	Seed      int64   // random seed
+}
+
+// Morris computes elementary effects using the trajectory design of
+// Morris M.D., 1991. Factorial sampling plans for preliminary computational experiments. Technometrics 33(2). pp. 161-174.
+// with μ* after Campolongo F., J. Cariboni, A. Saltelli, 2007. An effective screening design for sensitivity analysis of large models. Environmental Modelling & Software 22. pp. 1509-1518.
+// r: number of trajectories; nlevels: number of grid levels (even, typically 4); nboot: number of bootstrap resamples
+func (p *Problem) Morris(r, nlevels, nboot int) (*MorrisResult, error) {
+	f, names, err := p.build()
+	if err != nil {
+		return nil, err
+	}
+	if r < 2 {
+		return nil, fmt.Errorf("sensitivity.Morris: number of trajectories must be greater than 1")
+	}
+	if nlevels < 2 || nlevels%2 != 0 {
+		return nil, fmt.Errorf("sensitivity.Morris: number of levels must be even, %d given", nlevels)
+	}
+	rng, seed := rngseed.New(p.Seed)
+	d := len(names)
+	delta := float64(nlevels) / (2. * float64(nlevels-1))
+
+	// build trajectories: a random base point on the grid, then one step of ±delta per parameter in random order
+	u := make([][]float64, 0, r*(d+1))
+	steps := make([][]int, r)     // parameter changed at each step
+	signs := make([][]float64, r) // direction of each step
+	for t := range r {
+		x := make([]float64, d)
+		for i := range d {
+			x[i] = float64(rng.Intn(nlevels/2)) / float64(nlevels-1)
+			if rng.Float64() < .5 {
+				x[i] += delta // start from the upper half so that -delta remains in bounds
+			}
+		}
+		u = append(u, append([]float64(nil), x...))
+		steps[t] = rng.Perm(d)
+		signs[t] = make([]float64, d)
+		for _, i := range steps[t] {
+			if x[i]+delta <= 1.+1e-9 {
+				x[i] = math.Min(1., x[i]+delta)
+				signs[t][i] = 1.
+			} else {
+				x[i] -= delta
+				signs[t][i] = -1.
+			}
+			u = append(u, append([]float64(nil), x...))
+		}
+	}
+	y := evaluate(f, u)
+	for _, v := range y {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("sensitivity.Morris: invalid model response encountered, check the fitness function and parameter ranges")
+		}
+	}
+
+	ee := make([][]float64, d) // elementary effects [parameter][trajectory]
+	for i := range ee {
+		ee[i] = make([]float64, r)
+	}
+	for t := range r {
+		o := t * (d + 1)
+		for k, i := range steps[t] {
+			ee[i][t] = signs[t][i] * (y[o+k+1] - y[o+k]) / delta
+		}
+	}
+
+	res := MorrisResult{Params: names, Mu: make([]float64, d), Sigma: make([]float64, d), MuStar: make([]Index, d), R: r, Seed: seed}
+	all := identity(r)
+	for i := range d {
+		mustar := func(ix []int) float64 {
+			s := 0.
+			for _, t := range ix {
+				s += math.Abs(ee[i][t])
+			}
+			return s / float64(len(ix))
+		}
+		for _, v := range ee[i] {
+			res.Mu[i] += v
+		}
+		res.Mu[i] /= float64(r)
+		for _, v := range ee[i] {
+			res.Sigma[i] += (v - res.Mu[i]) * (v - res.Mu[i])
+		}
+		res.Sigma[i] = math.Sqrt(res.Sigma[i] / float64(r-1))
+		res.MuStar[i].Value = mustar(all)
+		res.MuStar[i].Lower, res.MuStar[i].Upper = bootstrap(r, nboot, rng, mustar)
+	}
+	return &res, nil
+}
+
+// String tabulates the elementary effect statistics
+func (r *MorrisResult) String() string {
+	s := fmt.Sprintf("Morris elementary effects (r=%d, %.0f%% confidence, seed %d)\n%12s %10s %10s %24s\n", r.R, conf*100., r.Seed, "param", "mu", "sigma", "mu*")
+	for i, p := range r.Params {
+		s += fmt.Sprintf("%12s %10.4f %10.4f %10.4f [%.4f,%.4f]\n", p, r.Mu[i], r.Sigma[i], r.MuStar[i].Value, r.MuStar[i].Lower, r.MuStar[i].Upper)
+	}
+	return s
+}
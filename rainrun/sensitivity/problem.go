@@ -0,0 +1,116 @@
+package sensitivity
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+
+	rr "github.com/maseology/goHydro/rainrun"
+	"github.com/maseology/goHydro/rainrun/sample"
+)
+
+const conf = .95 // bootstrap confidence level
+
+// Problem defines the model response being analysed
+type Problem struct {
+	Frc     *rr.Frc
+	Model   string                       // registered model name (see rainrun.Models())
+	Warmup  int                          // number of timesteps excluded from the response
+	Fitness func(o, s []float64) float64 // model response, e.g. objfunc.NSE; gaps in observations are removed
+	Seed    int64                        // random seed; 0: seeded from the clock (the seed used is reported in the result)
+}
+
+// Index is a sensitivity index with its bootstrap confidence interval
+type Index struct{ Value, Lower, Upper float64 }
+
+func (p *Problem) build() (func(u []float64) float64, []string, error) {
+	mi, ok := rr.Lookup(p.Model)
+	if !ok {
+		return nil, nil, fmt.Errorf("sensitivity: unrecognized model: %s", p.Model)
+	}
+	smpl, err := sample.Get(p.Model)
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.Fitness == nil {
+		return nil, nil, fmt.Errorf("sensitivity: fitness function required")
+	}
+	if p.Warmup >= p.Frc.Ndt-1 {
+		return nil, nil, fmt.Errorf("sensitivity: warm-up of %d exceeds the %d timesteps of forcing data", p.Warmup, p.Frc.Ndt)
+	}
+	obs := make([]float64, p.Frc.Ndt)
+	for i, v := range p.Frc.D {
+		obs[i] = v.Q
+	}
+	return func(u []float64) float64 {
+		m, err := mi.BuildFor(p.Frc.Timestep, smpl(u, p.Frc.Timestep)...)
+		if err != nil {
+			return math.NaN()
+		}
+		sim := make([]float64, p.Frc.Ndt)
+		for i, v := range p.Frc.D {
+			_, _, r, _ := m.Update(&v)
+			sim[i] = r
+		}
+		return p.Fitness(rr.Masked(obs[p.Warmup:], sim[p.Warmup:]))
+	}, mi.ParamNames(), nil
+}
+
+// evaluate runs the set of samples in parallel
+func evaluate(f func(u []float64) float64, u [][]float64) []float64 {
+	y := make([]float64, len(u))
+	var wg sync.WaitGroup
+	ch := make(chan int)
+	for range runtime.GOMAXPROCS(0) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range ch {
+				y[i] = f(u[i])
+			}
+		}()
+	}
+	for i := range u {
+		ch <- i
+	}
+	close(ch)
+	wg.Wait()
+	return y
+}
+
+// bootstrap returns the confidence interval of statistic stat computed over n
+// resampled (with replacement) sets of indices
+func bootstrap(n, nboot int, rng *rand.Rand, stat func(ix []int) float64) (lower, upper float64) {
+	if nboot <= 0 {
+		return math.NaN(), math.NaN()
+	}
+	b := make([]float64, 0, nboot)
+	ix := make([]int, n)
+	for range nboot {
+		for i := range ix {
+			ix[i] = rng.Intn(n)
+		}
+		if v := stat(ix); !math.IsNaN(v) {
+			b = append(b, v)
+		}
+	}
+	if len(b) == 0 {
+		return math.NaN(), math.NaN()
+	}
+	sort.Float64s(b)
+	q := func(p float64) float64 {
+		return b[int(math.Min(float64(len(b)-1), math.Max(0., math.Round(p*float64(len(b)-1)))))]
+	}
+	return q((1. - conf) / 2.), q((1. + conf) / 2.)
+}
+
+func identity(n int) []int {
+	ix := make([]int, n)
+	for i := range ix {
+		ix[i] = i
+	}
+	return ix
+}
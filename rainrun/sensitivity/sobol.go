@@ -0,0 +1,105 @@
+package sensitivity
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/maseology/goHydro/internal/rngseed"
+)
+
+// SobolResult holds the first-order and total-order Sobol indices of each parameter
+type SobolResult struct {
+	Params []string
+	S1, ST []Index
+	N      int   // base sample size; N(d+2) model evaluations
+	Seed   int64 // random seed
+}
+
+// Sobol computes first- and total-order sensitivity indices using the estimators of
+// Saltelli A., P. Annoni, I. Azzini, F. Campolongo, M. Ratto, S. Tarantola, 2010. Variance based sensitivity analysis of model output. Design and estimator for the total sensitivity index. Computer Physics Communications 181. pp. 259-270.
+// n: base sample size; nboot: number of bootstrap resamples used to compute confidence intervals
+func (p *Problem) Sobol(n, nboot int) (*SobolResult, error) {
+	f, names, err := p.build()
+	if err != nil {
+		return nil, err
+	}
+	if n < 2 {
+		return nil, fmt.Errorf("sensitivity.Sobol: base sample size must be greater than 1")
+	}
+	rng, seed := rngseed.New(p.Seed)
+	d := len(names)
+
+	// sample matrices A, B and AB_i (A with column i taken from B)
+	u := make([][]float64, n*(d+2))
+	for j := range n {
+		a, b := make([]float64, d), make([]float64, d)
+		for i := range d {
+			a[i], b[i] = rng.Float64(), rng.Float64()
+		}
+		u[j], u[n+j] = a, b
+		for i := range d {
+			ab := append([]float64(nil), a...)
+			ab[i] = b[i]
+			u[(2+i)*n+j] = ab
+		}
+	}
+	y := evaluate(f, u)
+	fa, fb := y[:n], y[n:2*n]
+
+	variance := func(ix []int) float64 {
+		m, v := 0., 0.
+		for _, j := range ix {
+			m += fa[j] + fb[j]
+		}
+		m /= float64(2 * len(ix))
+		for _, j := range ix {
+			v += (fa[j]-m)*(fa[j]-m) + (fb[j]-m)*(fb[j]-m)
+		}
+		return v / float64(2*len(ix)-1)
+	}
+	first := func(i int) func(ix []int) float64 {
+		fab := y[(2+i)*n : (3+i)*n]
+		return func(ix []int) float64 {
+			s := 0.
+			for _, j := range ix {
+				s += fb[j] * (fab[j] - fa[j])
+			}
+			return s / float64(len(ix)) / variance(ix)
+		}
+	}
+	total := func(i int) func(ix []int) float64 { // Jansen (1999)
+		fab := y[(2+i)*n : (3+i)*n]
+		return func(ix []int) float64 {
+			s := 0.
+			for _, j := range ix {
+				s += (fa[j] - fab[j]) * (fa[j] - fab[j])
+			}
+			return s / float64(2*len(ix)) / variance(ix)
+		}
+	}
+
+	for _, v := range y {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("sensitivity.Sobol: invalid model response encountered, check the fitness function and parameter ranges")
+		}
+	}
+	r := SobolResult{Params: names, S1: make([]Index, d), ST: make([]Index, d), N: n, Seed: seed}
+	all := identity(n)
+	for i := range d {
+		s1, st := first(i), total(i)
+		r.S1[i].Value = s1(all)
+		r.S1[i].Lower, r.S1[i].Upper = bootstrap(n, nboot, rng, s1)
+		r.ST[i].Value = st(all)
+		r.ST[i].Lower, r.ST[i].Upper = bootstrap(n, nboot, rng, st)
+	}
+	return &r, nil
+}
+
+// String tabulates the Sobol indices
+func (r *SobolResult) String() string {
+	s := fmt.Sprintf("Sobol indices (N=%d, %.0f%% confidence, seed %d)\n%12s %24s %24s\n", r.N, conf*100., r.Seed, "param", "S1", "ST")
+	for i, p := range r.Params {
+		s += fmt.Sprintf("%12s %8.3f [%6.3f,%6.3f] %8.3f [%6.3f,%6.3f]\n", p, r.S1[i].Value, r.S1[i].Lower, r.S1[i].Upper, r.ST[i].Value, r.ST[i].Lower, r.ST[i].Upper)
+	}
+	return s
+}
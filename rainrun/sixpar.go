@@ -73,3 +73,22 @@ func (m *SIXPAR) Storage() float64 {
 
 // // Ndim returns the number of dimensions
 // func (m *SIXPAR) Ndim() int { return 6 }
+
+// State returns the model state: [up, low]
+func (m *SIXPAR) State() []float64 {
+	return []float64{m.up.sto, m.low.sto}
+}
+
+// SetState restores the model state returned by State()
+func (m *SIXPAR) SetState(x []float64) error {
+	if err := checkState("SIXPAR", x, 2); err != nil {
+		return err
+	}
+	m.up.sto, m.low.sto = x[0], x[1]
+	return nil
+}
+
+// StateBounds returns the bounds of the state variables returned by State()
+func (m *SIXPAR) StateBounds() (lo, hi []float64) {
+	return storages(m.up.cap, m.low.cap)
+}
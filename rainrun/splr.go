@@ -35,6 +35,25 @@ func (m *SPLR) Storage() float64 {
 	return m.s1.sto + m.s2.sto + m.s3.sto
 }
 
+// State returns the model state: [s1, s2, s3]
+func (m *SPLR) State() []float64 {
+	return []float64{m.s1.sto, m.s2.sto, m.s3.sto}
+}
+
+// SetState restores the model state returned by State()
+func (m *SPLR) SetState(x []float64) error {
+	if err := checkState("SPLR", x, 3); err != nil {
+		return err
+	}
+	m.s1.sto, m.s2.sto, m.s3.sto = x[0], x[1], x[2]
+	return nil
+}
+
+// StateBounds returns the bounds of the state variables returned by State()
+func (m *SPLR) StateBounds() (lo, hi []float64) {
+	return storages(unbounded, unbounded, unbounded)
+}
+
 /////////////////////////////////////////////////////////////
 ////////////////////////////////////OLD//////////////////////
 /////////////////////////////////////////////////////////////
@@ -65,3 +65,22 @@ func (t *Tank) Update(p, ep float64) (float64, float64, float64) {
 
 	return a, q1 + q2 + q3 + q4, deepperc
 }
+
+// State returns the model state: [h1, h2, h3, h4]
+func (t *Tank) State() []float64 {
+	return []float64{t.h1, t.h2, t.h3, t.h4}
+}
+
+// SetState restores the model state returned by State()
+func (t *Tank) SetState(x []float64) error {
+	if err := checkState("Tank", x, 4); err != nil {
+		return err
+	}
+	t.h1, t.h2, t.h3, t.h4 = x[0], x[1], x[2], x[3]
+	return nil
+}
+
+// StateBounds returns the bounds of the state variables returned by State()
+func (t *Tank) StateBounds() (lo, hi []float64) {
+	return storages(unbounded, unbounded, unbounded, unbounded)
+}
@@ -56,3 +56,22 @@ func (tm *TOPMODEL) DepthToWT(drelLocal float64) float64 {
 	di := tm.m*drelLocal + tm.d
 	return di / tm.n
 }
+
+// State returns the model state: [d]
+func (tm *TOPMODEL) State() []float64 {
+	return []float64{tm.d}
+}
+
+// SetState restores the model state returned by State()
+func (tm *TOPMODEL) SetState(x []float64) error {
+	if err := checkState("TOPMODEL", x, 1); err != nil {
+		return err
+	}
+	tm.d = x[0]
+	return nil
+}
+
+// StateBounds returns the bounds of the state variables returned by State()
+func (tm *TOPMODEL) StateBounds() (lo, hi []float64) {
+	return []float64{math.Inf(-1)}, []float64{unbounded} // the mean deficit is negative when saturated
+}
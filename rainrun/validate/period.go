@@ -0,0 +1,64 @@
+package validate
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	rr "github.com/maseology/goHydro/rainrun"
+)
+
+// Period is a named set of dates over which a model is scored
+type Period struct {
+	Name       string
+	Start, End time.Time // inclusive; zero values extend to the ends of the record
+	Years      []int     // optional: limits the period to a set of (non-contiguous) calendar years
+}
+
+// Contains returns true if the date falls within the period
+func (p Period) Contains(t time.Time) bool {
+	if !p.Start.IsZero() && t.Before(p.Start) {
+		return false
+	}
+	if !p.End.IsZero() && t.After(p.End) {
+		return false
+	}
+	if len(p.Years) > 0 {
+		return slices.Contains(p.Years, t.Year())
+	}
+	return true
+}
+
+// SplitSample divides the record into a calibration period ending before
+// the split date, and a validation period beginning on the split date
+func SplitSample(split time.Time) (cal, val Period) {
+	cal = Period{Name: "calibration", End: split.Add(-time.Nanosecond)}
+	val = Period{Name: "validation", Start: split}
+	return
+}
+
+// DifferentialSplit divides the years of record into the wettest and
+// driest halves, based on annual precipitation, for differential split-sample testing
+func DifferentialSplit(frc *rr.Frc) (wet, dry Period, err error) {
+	if len(frc.DT) != len(frc.D) {
+		return wet, dry, fmt.Errorf("validate.DifferentialSplit: forcing dates required")
+	}
+	ap := make(map[int]float64)
+	for i, v := range frc.D {
+		ap[frc.DT[i].Year()] += v.Precip()
+	}
+	if len(ap) < 2 {
+		return wet, dry, fmt.Errorf("validate.DifferentialSplit: at least 2 years of record required, %d given", len(ap))
+	}
+	yrs := make([]int, 0, len(ap))
+	for y := range ap {
+		yrs = append(yrs, y)
+	}
+	sort.Slice(yrs, func(i, j int) bool { return ap[yrs[i]] > ap[yrs[j]] })
+	wet = Period{Name: "wet", Years: yrs[:len(yrs)/2]}
+	dry = Period{Name: "dry", Years: yrs[len(yrs)/2:]}
+	sort.Ints(wet.Years)
+	sort.Ints(dry.Years)
+	return
+}
@@ -0,0 +1,153 @@
+package validate
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	rr "github.com/maseology/goHydro/rainrun"
+	"github.com/maseology/goHydro/rainrun/optimize"
+	"github.com/maseology/mmio"
+)
+
+var seasons = [4]string{"DJF", "MAM", "JJA", "SON"}
+
+// Score holds the objective function values of a set of timesteps
+type Score struct {
+	Group, Name string  // group: "period", "season" or "year"
+	N           int     // number of (observed) timesteps scored
+	Coverage    float64 // fraction of timesteps observed
+	F           []float64
+}
+
+// Report of a validation run. Scores are objective function values as
+// minimized by optimize.Calibrate (e.g., 1-NSE), so that calibration and
+// validation scores are directly comparable.
+type Report struct {
+	Model      string
+	P          []float64
+	Objectives []string
+	Scores     []Score // ordered: periods, seasons, years
+	frc        *rr.Frc
+	o, s, g    []float64
+	warmup     int
+}
+
+// Validate runs a parameterized model over the entire record, scoring each
+// period, season (DJF, MAM, JJA, SON) and calendar year following the warm-up;
+// gaps in the observed record are excluded, with each score reporting its coverage
+func Validate(frc *rr.Frc, mdl string, p []float64, warmup int, objs []optimize.Objective, periods ...Period) (*Report, error) {
+	if len(frc.DT) != len(frc.D) {
+		return nil, fmt.Errorf("validate.Validate: forcing dates required")
+	}
+	if len(objs) == 0 {
+		objs = []optimize.Objective{optimize.NSE(1.)}
+	}
+	m, err := rr.NewModelAt(mdl, frc.Timestep, p...)
+	if err != nil {
+		return nil, err
+	}
+
+	r := Report{Model: mdl, P: p, frc: frc, warmup: warmup}
+	for _, o := range objs {
+		r.Objectives = append(r.Objectives, o.Name)
+	}
+	r.o, r.s, r.g = make([]float64, len(frc.D)), make([]float64, len(frc.D)), make([]float64, len(frc.D))
+	for i, v := range frc.D {
+		_, _, q, g := m.Update(&v)
+		r.o[i] = v.Q
+		r.s[i] = q
+		r.g[i] = g
+	}
+
+	msk := frc.Mask()
+	score := func(group, name string, in func(t time.Time) bool) Score {
+		sc := Score{Group: group, Name: name, F: make([]float64, len(objs))}
+		var o, s []float64
+		n := 0
+		for i := warmup; i < len(frc.D); i++ {
+			if in(frc.DT[i]) {
+				n++
+				if msk[i] {
+					o = append(o, r.o[i])
+					s = append(s, r.s[i])
+				}
+			}
+		}
+		sc.N = len(o)
+		if n > 0 {
+			sc.Coverage = float64(sc.N) / float64(n)
+		}
+		for k, obj := range objs {
+			if sc.N < 2 {
+				sc.F[k] = math.NaN()
+				continue
+			}
+			sc.F[k] = obj.F(o, s)
+		}
+		return sc
+	}
+
+	r.Scores = append(r.Scores, score("period", "all", func(time.Time) bool { return true }))
+	for _, pp := range periods {
+		r.Scores = append(r.Scores, score("period", pp.Name, pp.Contains))
+	}
+	for k, ss := range seasons {
+		r.Scores = append(r.Scores, score("season", ss, func(t time.Time) bool { return season(t) == k }))
+	}
+	if warmup < len(frc.DT) {
+		for y := frc.DT[warmup].Year(); y <= frc.DT[len(frc.DT)-1].Year(); y++ {
+			r.Scores = append(r.Scores, score("year", strconv.Itoa(y), func(t time.Time) bool { return t.Year() == y }))
+		}
+	}
+	return &r, nil
+}
+
+func season(t time.Time) int {
+	return int(t.Month()) % 12 / 3
+}
+
+// String summarizes the validation scores
+func (r *Report) String() string {
+	s := fmt.Sprintf("%10s %10s %6s %6s", "group", "name", "n", "cov")
+	for _, o := range r.Objectives {
+		s += fmt.Sprintf(" %10s", o)
+	}
+	s += "\n"
+	for _, sc := range r.Scores {
+		s += fmt.Sprintf("%10s %10s %6d %6.3f", sc.Group, sc.Name, sc.N, sc.Coverage)
+		for _, f := range sc.F {
+			s += fmt.Sprintf(" %10.4f", f)
+		}
+		s += "\n"
+	}
+	return s
+}
+
+// Write saves the validation scores (prfx.validation.csv) alongside the hydrograph and monthly summaries
+func (r *Report) Write(prfx string) {
+	n := len(r.Scores)
+	ig, in, ic, iv := make([]interface{}, n), make([]interface{}, n), make([]interface{}, n), make([]interface{}, n)
+	cols := make([][]interface{}, len(r.Objectives))
+	for k := range cols {
+		cols[k] = make([]interface{}, n)
+	}
+	for i, sc := range r.Scores {
+		ig[i] = sc.Group
+		in[i] = sc.Name
+		ic[i] = sc.N
+		iv[i] = sc.Coverage
+		for k, f := range sc.F {
+			cols[k][i] = f
+		}
+	}
+	hdr := "group,name,n,coverage"
+	for _, o := range r.Objectives {
+		hdr += "," + o
+	}
+	mmio.WriteCSV(prfx+".validation.csv", hdr, append([][]interface{}{ig, in, ic, iv}, cols...)...)
+	rr.SumHydrograph(r.frc, r.o, r.s, r.g, prfx)
+	w := min(r.warmup, len(r.o))
+	rr.SumMonthly(r.frc.DT[w:], r.o[w:], r.s[w:], r.frc.Timestep, 1., prfx)
+}
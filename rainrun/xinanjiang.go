@@ -0,0 +1,133 @@
+package rainrun
+
+import "math"
+
+// Xinanjiang model
+// ref: Zhao, R.J., 1992. The Xinanjiang model applied in China. Journal of Hydrology 135. pp. 371-381.
+// three-layer evaporation, saturation-excess runoff generation and three-source (surface, interflow, groundwater) separation
+type Xinanjiang struct {
+	wu, wl, wd, s, qi, qg           float64 // tension water (upper, lower, deep), free water, interflow and groundwater
+	wum, wlm, wdm, b, c, im, sm, ex float64
+	ki, kg, ci, cg                  float64
+}
+
+// New Xinanjiang constructor
+// [wm, x, y, b, c, im, sm, ex, kig, kif, ci, cg]
+func (m *Xinanjiang) New(p ...float64) {
+	if p[0] <= 0. || fracCheck(p[1]) || fracCheck(p[2]) || fracCheck(p[4]) || fracCheck(p[5]) || fracCheck(p[8]) || p[8] >= 1. || fracCheck(p[9]) || fracCheck(p[10]) || fracCheck(p[11]) {
+		panic("Xinanjiang input error")
+	}
+	m.wum = p[1] * p[0]           // upper layer tension water capacity
+	m.wlm = p[2] * (p[0] - m.wum) // lower layer tension water capacity
+	m.wdm = p[0] - m.wum - m.wlm  // deep layer tension water capacity
+	m.b = p[3]                    // tension water capacity curve exponent
+	m.c = p[4]                    // deep layer evapotranspiration coefficient
+	m.im = p[5]                   // impervious fraction
+	m.sm = p[6]                   // free water storage capacity
+	m.ex = p[7]                   // free water capacity curve exponent
+	m.ki = p[8] * p[9]            // free water outflow coefficient to interflow (kig: total outflow coefficient; kif: fraction to interflow)
+	m.kg = p[8] * (1. - p[9])     // free water outflow coefficient to groundwater
+	m.ci = p[10]                  // interflow recession
+	m.cg = p[11]                  // groundwater recession
+}
+
+// Update state for daily inputs
+func (m *Xinanjiang) Update(p, ep float64) (float64, float64, float64) {
+	// three-layer evaporation
+	var eu, el, ed float64
+	if m.wu+p >= ep {
+		eu = ep
+	} else {
+		eu = m.wu + p
+		r := ep - eu
+		switch {
+		case m.wlm > 0. && m.wl >= m.c*m.wlm:
+			el = r * m.wl / m.wlm
+		case m.wl >= m.c*r:
+			el = m.c * r
+		default:
+			el = m.wl
+			ed = math.Min(m.wd, m.c*r-el)
+		}
+	}
+	e := eu + el + ed
+	pe := p - e
+
+	// runoff generation (saturation excess over the tension water capacity curve)
+	wm := m.wum + m.wlm + m.wdm
+	w := m.wu + m.wl + m.wd
+	r := 0.
+	if pe > 0. {
+		wmm := wm * (1. + m.b)
+		a := wmm * (1. - math.Pow(math.Max(0., 1.-w/wm), 1./(1.+m.b)))
+		if pe+a < wmm {
+			r = pe - (wm - w) + wm*math.Pow(1.-(pe+a)/wmm, 1.+m.b)
+		} else {
+			r = pe - (wm - w)
+		}
+		r = math.Max(0., math.Min(r, pe))
+	}
+
+	// update tension water, filling from the upper layer down
+	m.wu += p - eu - r
+	m.wl -= el
+	m.wd -= ed
+	if m.wu > m.wum {
+		m.wl += m.wu - m.wum
+		m.wu = m.wum
+	}
+	if m.wl > m.wlm {
+		m.wd += m.wl - m.wlm
+		m.wl = m.wlm
+	}
+	m.wu, m.wl, m.wd = math.Max(0., m.wu), math.Max(0., m.wl), math.Max(0., math.Min(m.wd, m.wdm))
+
+	// source separation over the runoff-producing area fraction
+	rs, ri, rg := 0., 0., 0.
+	if r > 0. {
+		fr := r / pe
+		smm := m.sm * (1. + m.ex)
+		au := smm * (1. - math.Pow(math.Max(0., 1.-m.s/m.sm), 1./(1.+m.ex)))
+		if pe+au < smm {
+			rs = fr * (pe + m.s - m.sm + m.sm*math.Pow(1.-(pe+au)/smm, 1.+m.ex))
+		} else {
+			rs = fr * (pe + m.s - m.sm)
+		}
+		rs = math.Max(0., math.Min(rs, r))
+		m.s = math.Min(m.sm, m.s+(r-rs)/fr)
+		ri = m.ki * m.s * fr
+		rg = m.kg * m.s * fr
+		m.s *= 1. - m.ki - m.kg
+	}
+
+	// routing: direct runoff from impervious area and surface, linear reservoirs for interflow and groundwater
+	rim := m.im * math.Max(0., pe)
+	pv := 1. - m.im
+	m.qi = m.ci*m.qi + (1.-m.ci)*ri*pv
+	m.qg = m.cg*m.qg + (1.-m.cg)*rg*pv
+	return e, rim + rs*pv + m.qi + m.qg, rg * pv
+}
+
+// Storage returns total storage
+func (m *Xinanjiang) Storage() float64 {
+	return m.wu + m.wl + m.wd + m.s
+}
+
+// State returns the model state: [wu, wl, wd, s, qi, qg]
+func (m *Xinanjiang) State() []float64 {
+	return []float64{m.wu, m.wl, m.wd, m.s, m.qi, m.qg}
+}
+
+// SetState restores the model state returned by State()
+func (m *Xinanjiang) SetState(x []float64) error {
+	if err := checkState("Xinanjiang", x, 6); err != nil {
+		return err
+	}
+	m.wu, m.wl, m.wd, m.s, m.qi, m.qg = x[0], x[1], x[2], x[3], x[4], x[5]
+	return nil
+}
+
+// StateBounds returns the bounds of the state variables returned by State()
+func (m *Xinanjiang) StateBounds() (lo, hi []float64) {
+	return storages(m.wum, m.wlm, m.wdm, m.sm, unbounded, unbounded)
+}
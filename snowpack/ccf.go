@@ -21,6 +21,7 @@ func NewDefaultCCF() CCF {
 	c.ddf = ddfi // degree-day/melt factor; range .001 to .008 m/°C/d  (pg.275) -- NOTE: this is an initial value if adjustDegreeDayFactor() and ddfc is used
 	c.ddfc = 1.1 // DDF adjustment factor based on pack density, see DeWalle and Rango, pg. 275; Ref: Martinec (1960)
 	c.tb = 0.    // base/critical temperature (°C)
+	c.df = 1.    // daily timestep
 	return c
 }
 
@@ -34,6 +35,7 @@ func NewCCF(ccf, ddfc, baseT, tsf, denscoef float64) CCF {
 	c.tb = baseT          // base/critical temperature (°C)
 	c.tsf = tsf           // TSF (surface temperature factor), 0.1-0.5 have been used
 	c.denscoef = denscoef // coefficient to the densification factor
+	c.df = 1.             // daily timestep
 	return c
 }
 
@@ -58,7 +60,7 @@ func (c *CCF) Update(r, s, t float64) (melt, throughfall float64, err error) {
 	}
 
 	c.adjustDegreeDayFactor()
-	potmelt := c.ddf * df * (t - c.tb) // [m·°C-1·d-1]
+	potmelt := c.ddf * c.df * (t - c.tb) // [m·°C-1·d-1]
 	if potmelt > 0. {
 		if potmelt >= c.swe-c.lwc {
 			potmelt = c.swe - c.lwc
@@ -103,7 +105,7 @@ func (c *CCF) Update(r, s, t float64) (melt, throughfall float64, err error) {
 
 func (c *CCF) updateSurfaceTemperature(t float64) { // pg.279
 	if c.swe > 0. {
-		c.ts += c.tsf * df * (t - c.ts)
+		c.ts += c.tsf * c.df * (t - c.ts)
 		if c.ts > 0. {
 			c.ts = 0.
 		}
@@ -126,7 +128,7 @@ func (c *CCF) satisfyColdContent(t float64) {
 			c.den = 0.
 		}
 	} else {
-		c.cc += c.ccf * df * (c.ts - t)
+		c.cc += c.ccf * c.df * (c.ts - t)
 		if c.cc <= 0. {
 			c.cc = 0.
 			c.ts = 0.
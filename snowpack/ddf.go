@@ -28,6 +28,7 @@ func NewDDF(ddfc, baseT, denscoef float64) DDF {
 	}
 	d.tb = baseT          // base/critical temperature (°C)
 	d.denscoef = denscoef // coefficient to the densification factor
+	d.df = 1.             // daily timestep
 	return d
 }
 
@@ -40,7 +41,7 @@ func (d *DDF) Update(r, s, t float64) (melt, throughfall float64) {
 	}
 
 	d.adjustDegreeDayFactor()
-	potmelt := d.ddf * df * (t - d.tb) // [m·°C-1·d-1]
+	potmelt := d.ddf * d.df * (t - d.tb) // [m·°C-1·d-1]
 	if potmelt > 0. {
 		if potmelt >= d.swe-d.lwc {
 			potmelt = d.swe - d.lwc
@@ -24,13 +24,16 @@ const (
 	scap   = 0.05 // snowpack liquid water retention capacity (≈0.05; DeWalle and Rango, 2008)
 	// denscoef = 1.   // coefficient to the densification factor
 	cdt = 5.5 // [kg/m³/°C] slope of density-temperature relationship (see func.go SnowFallDensity())
-
-	// other
-	df = 1. // [ts/day] day factor (adjust when daily timesteps are not used)
 )
 
 type snowpack struct {
 	swe, den, lwc, tb, denscoef float64 // tb: base/critical temperature; tsf: surface temperature factor; denscoef: coefficient to the densification factor
+	df                          float64 // [day/ts] day factor, 1 for daily timesteps
+}
+
+// SetTimestep sets the day factor for a timestep ts [s] (default daily)
+func (s *snowpack) SetTimestep(ts float64) {
+	s.df = ts / 86400.
 }
 
 func inputDataCheck(r, s, t float64) error {
@@ -137,7 +140,7 @@ func (s *snowpack) internalFreeze(sweAffected float64) {
 func (s *snowpack) densify() {
 	if s.den > 0. {
 		if s.den < pi {
-			f := math.Pow(pi/s.frozenPackDensity(), df*s.denscoef)
+			f := math.Pow(pi/s.frozenPackDensity(), s.df*s.denscoef)
 			if f > 1. {
 				if s.den*f > pi {
 					s.den = pi
@@ -0,0 +1,44 @@
+package snowpack
+
+import "fmt"
+
+// State returns the snowpack state variables: [swe, den, lwc]
+func (s *snowpack) State() []float64 {
+	return []float64{s.swe, s.den, s.lwc}
+}
+
+func (s *snowpack) setState(x []float64) {
+	s.swe, s.den, s.lwc = x[0], x[1], x[2]
+}
+
+// State returns the DDF state variables: [swe, den, lwc, ddf]
+func (d *DDF) State() []float64 {
+	return append(d.snowpack.State(), d.ddf)
+}
+
+// SetState restores the DDF state variables returned by State()
+func (d *DDF) SetState(x []float64) error {
+	if len(x) != 4 {
+		return fmt.Errorf("DDF.SetState: state length %d, expecting 4", len(x))
+	}
+	d.snowpack.setState(x)
+	d.ddf = x[3]
+	return nil
+}
+
+// State returns the CCF state variables: [swe, den, lwc, ddf, cc, ts]
+func (c *CCF) State() []float64 {
+	return append(c.DDF.State(), c.cc, c.ts)
+}
+
+// SetState restores the CCF state variables returned by State()
+func (c *CCF) SetState(x []float64) error {
+	if len(x) != 6 {
+		return fmt.Errorf("CCF.SetState: state length %d, expecting 6", len(x))
+	}
+	if err := c.DDF.SetState(x[:4]); err != nil {
+		return err
+	}
+	c.cc, c.ts = x[4], x[5]
+	return nil
+}
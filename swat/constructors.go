@@ -1,7 +1,7 @@
 package swat
 
 import (
-	"log"
+	"fmt"
 	"math"
 )
 
@@ -51,7 +51,7 @@ specifications:
 // GWDELAY: (delta_gw) delay time for aquifer recharge [days]
 // GWQMN: (aq_shthr) threshold water level in aquifer for baseflow [mm]
 // ALPHABF: (alpha_bf) baseflow recession coeficient (1/k)
-func (b *SubBasin) New(HRUs []*HRU, Chn *Channel, SUBKM, SLSUBBSN, CHL, CHS, CHN, SURLAG, GWDELAY, ALPHABF float64) {
+func (b *SubBasin) New(HRUs []*HRU, Chn *Channel, SUBKM, SLSUBBSN, CHL, CHS, CHN, SURLAG, GWDELAY, ALPHABF float64) error {
 	b.Ca = SUBKM // subbasin contributing area [km²]
 	b.surlag = SURLAG
 	b.dgw = GWDELAY // the delay of soil zone percolation to aquifer [days]
@@ -80,8 +80,9 @@ func (b *SubBasin) New(HRUs []*HRU, Chn *Channel, SUBKM, SLSUBBSN, CHL, CHS, CHN
 	}
 	b.tconc = tconc(wslp, SLSUBBSN, wovn, CHL, CHS, CHN, SUBKM)
 	if math.IsNaN(b.tconc) {
-		log.Fatalf("SubBasin.New error: tconc is NaN\n")
+		return fmt.Errorf("SubBasin.New error: tconc is NaN")
 	}
+	return nil
 }
 
 // tconc returns the time of concentration to subbasin outlet [hr]
@@ -138,7 +139,7 @@ func (sl *SoilLayer) New(CLAY, SOLBD, SOLAWC, SOLK float64) {
 // CHL: (L_ch) length of main channel [km]
 // CHS: (slp_ch) length of main channel [-]
 // CHN: (n) Manning's n value for the main channel
-func (c *Channel) New(CHW, CHD, CHL, CHS, CHN float64) {
+func (c *Channel) New(CHW, CHD, CHL, CHS, CHN float64) error {
 	c.d = CHD           // initial flow depth [m]
 	c.len = CHL * 1000. // converting to [m]
 	c.zch = zch
@@ -168,6 +169,7 @@ func (c *Channel) New(CHW, CHD, CHL, CHS, CHN float64) {
 	}
 	c.sc = 2. * secperday / (2.*tt + secperday) // pg.434
 	if c.sc < 0. || c.sc > 1. || (math.IsNaN(c.sc) && c.len > 0.) {
-		log.Fatalf("Channel.New error: SC = %f\n", c.sc)
+		return fmt.Errorf("Channel.New error: SC = %f", c.sc)
 	}
+	return nil
 }
@@ -2,13 +2,28 @@ package swat
 
 import (
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 
 	"github.com/maseology/mmio"
 )
 
+// LoadError reports a model input file that could not be loaded
+type LoadError struct {
+	File string
+	Line int // 0 when not specific to a line
+	Err  error
+}
+
+func (e *LoadError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("swat.Load: %s line %d: %v", e.File, e.Line, e.Err)
+	}
+	return fmt.Sprintf("swat.Load: %s: %v", e.File, e.Err)
+}
+
+func (e *LoadError) Unwrap() error { return e.Err }
+
 // temporary data loaders
 type lbsn struct{ SUBKM, SLSUBBSN, CHL, CHS, CHN, SURLAG, GWDELAY, ALPHABF float64 }
 type lrte struct{ CHL, CHS, CHW, CHD, CHN float64 }
@@ -17,15 +32,19 @@ type lhru struct {
 	HRUFR, HRUSLP, OVN, CN2, CV, ESCO, CLAY, SOLBD, SOLAWC, SOLK float64
 }
 
-// Load a set of .csv files to build a SWAT model structure
-func Load(fbsn, fhru, frte, ftopo string) (WaterShed, []int) {
+// Load a set of .csv files to build a SWAT model structure; returns a
+// *LoadError when a file cannot be read or holds an invalid model structure
+func Load(fbsn, fhru, frte, ftopo string) (WaterShed, []int, error) {
 
 	ibsn, err := mmio.ReadCSV(fbsn) // SWSID,SUBKM,SLSUBBSN,CHL,CHS,CHN,SURLAG,GWDELAY,ALPHABF
 	if err != nil {
-		log.Fatalf("main: Error reading %s: %v\n", fbsn, err)
+		return nil, nil, &LoadError{fbsn, 0, err}
 	}
 	sbsn := make(map[int]lbsn, len(ibsn))
-	for _, s := range ibsn {
+	for i, s := range ibsn {
+		if len(s) < 9 {
+			return nil, nil, &LoadError{fbsn, i + 2, fmt.Errorf("%d fields, expecting 9", len(s))}
+		}
 		sb := lbsn{
 			SUBKM:    s[1],
 			SLSUBBSN: s[2],
@@ -42,10 +61,13 @@ func Load(fbsn, fhru, frte, ftopo string) (WaterShed, []int) {
 
 	irte, err := mmio.ReadCSV(frte) // SWSID,CHL,CHS,CHW,CHD,CHN
 	if err != nil {
-		log.Fatalf("main: Error reading %s: %v\n", frte, err)
+		return nil, nil, &LoadError{frte, 0, err}
 	}
 	srte := make(map[int]lrte, len(irte))
-	for _, s := range irte {
+	for i, s := range irte {
+		if len(s) < 6 {
+			return nil, nil, &LoadError{frte, i + 2, fmt.Errorf("%d fields, expecting 6", len(s))}
+		}
 		sr := lrte{
 			CHL: s[1],
 			CHS: s[2],
@@ -57,12 +79,15 @@ func Load(fbsn, fhru, frte, ftopo string) (WaterShed, []int) {
 	}
 	fmt.Printf("%d channels read\n", len(irte))
 
-	ihru, err := mmio.ReadCSV(fhru) // SWSID,HRUFR,HRUSLP,OVN,CN2,CV,CLAY,SOLBD,SOLAWC,SOLK
+	ihru, err := mmio.ReadCSV(fhru) // SWSID,HRUFR,HRUSLP,OVN,CN2,CV,ESCO,CLAY,SOLBD,SOLAWC,SOLK
 	if err != nil {
-		log.Fatalf("main: Error reading %s: %v\n", fhru, err)
+		return nil, nil, &LoadError{fhru, 0, err}
 	}
 	shru := make(map[int]lhru, len(ihru))
 	for i, s := range ihru {
+		if len(s) < 11 {
+			return nil, nil, &LoadError{fhru, i + 2, fmt.Errorf("%d fields, expecting 11", len(s))}
+		}
 		sh := lhru{
 			SWSID:  int(s[0]),
 			HRUFR:  s[1],
@@ -82,16 +107,19 @@ func Load(fbsn, fhru, frte, ftopo string) (WaterShed, []int) {
 
 	itopo, err := mmio.ReadTextLines(ftopo) // sub-basin topology
 	if err != nil {
-		log.Fatalf("main: Error (1) reading %s: %v\n", ftopo, err)
+		return nil, nil, &LoadError{ftopo, 0, err}
 	}
 	topo = make(map[int][]int, len(itopo)) // SubBasin topology {to:[]from}
-	for _, s := range itopo {
+	for ln, s := range itopo {
 		s1 := strings.Split(s, ",")
+		if len(s1) < 2 {
+			return nil, nil, &LoadError{ftopo, ln + 1, fmt.Errorf("expecting \"to,from from ..\"")}
+		}
 		s2 := strings.Split(s1[1], " ")
 		icoll := make([]int, 0, len(s2))
 		i1, err := strconv.Atoi(strings.TrimSpace(s1[0]))
 		if err != nil {
-			log.Fatalf("main: Error (2) reading %s: %v\n", ftopo, err)
+			return nil, nil, &LoadError{ftopo, ln + 1, err}
 		}
 		for _, s := range s2 {
 			if len(s) == 0 {
@@ -99,7 +127,7 @@ func Load(fbsn, fhru, frte, ftopo string) (WaterShed, []int) {
 			}
 			i2, err := strconv.Atoi(strings.TrimSpace(s))
 			if err != nil {
-				log.Fatalf("main: Error (3) reading %s: %v\n", ftopo, err)
+				return nil, nil, &LoadError{ftopo, ln + 1, err}
 			}
 			icoll = append(icoll, i2)
 		}
@@ -122,13 +150,20 @@ func Load(fbsn, fhru, frte, ftopo string) (WaterShed, []int) {
 	chns := make(map[int]*Channel, len(sbsn))
 	for i, s := range srte {
 		var chn Channel
-		chn.New(s.CHW, s.CHD, s.CHL, s.CHS, s.CHN)
+		if err := chn.New(s.CHW, s.CHD, s.CHL, s.CHS, s.CHN); err != nil {
+			return nil, nil, &LoadError{frte, 0, fmt.Errorf("subbasin %d: %v", i, err)}
+		}
 		chns[i] = &chn
 	}
 	sbsns := make(map[int]*SubBasin, len(sbsn))
 	for i, s := range sbsn {
+		if _, ok := chns[i]; !ok {
+			return nil, nil, &LoadError{frte, 0, fmt.Errorf("no channel given for subbasin %d", i)}
+		}
 		var bsn SubBasin
-		bsn.New(hrus[i], chns[i], s.SUBKM, s.SLSUBBSN, s.CHL, s.CHS, s.CHN, s.SURLAG, s.GWDELAY, s.ALPHABF)
+		if err := bsn.New(hrus[i], chns[i], s.SUBKM, s.SLSUBBSN, s.CHL, s.CHS, s.CHN, s.SURLAG, s.GWDELAY, s.ALPHABF); err != nil {
+			return nil, nil, &LoadError{fbsn, 0, fmt.Errorf("subbasin %d: %v", i, err)}
+		}
 		sbsns[i] = &bsn
 	}
 
@@ -141,5 +176,9 @@ func Load(fbsn, fhru, frte, ftopo string) (WaterShed, []int) {
 		}
 	}
 
-	return sbsns, topoOrder()
+	ord, err := topoOrder()
+	if err != nil {
+		return nil, nil, &LoadError{ftopo, 0, err}
+	}
+	return sbsns, ord, nil
 }
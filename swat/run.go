@@ -0,0 +1,50 @@
+package swat
+
+import (
+	"fmt"
+
+	"github.com/maseology/goHydro/forcing"
+)
+
+// Run simulates the watershed over the timesteps of frc, subbasins taken in
+// order ord (see Load); metxr maps subbasin IDs to forcing location IDs.
+// Daily precipitation and potential evaporation are required. Returns the
+// outflow of every subbasin [m³/d].
+func Run(ws WaterShed, ord []int, frc *forcing.Forcing, metxr map[int]int) (map[int][]float64, error) {
+	if frc.IntervalSec != 86400. {
+		return nil, fmt.Errorf("swat.Run: daily forcings required, %gs given", frc.IntervalSec)
+	}
+	if frc.Ea == nil {
+		return nil, fmt.Errorf("swat.Run: potential evaporation required")
+	}
+	loc := make(map[int]int, len(ord))
+	for _, sbid := range ord {
+		if _, ok := ws[sbid]; !ok {
+			return nil, fmt.Errorf("swat.Run: subbasin %d not found", sbid)
+		}
+		lid, ok := metxr[sbid]
+		if !ok {
+			return nil, fmt.Errorf("swat.Run: subbasin %d not given a forcing location", sbid)
+		}
+		if loc[sbid], ok = frc.Index(lid); !ok {
+			return nil, fmt.Errorf("swat.Run: subbasin %d forcing location %d not found", sbid, lid)
+		}
+	}
+
+	q := make(map[int][]float64, len(ord))
+	for _, sbid := range ord {
+		q[sbid] = make([]float64, frc.Nstep())
+	}
+	for j := range frc.Nstep() {
+		vin := make(map[int]float64, len(ws))
+		for _, sbid := range ord {
+			bsn, i := ws[sbid], loc[sbid]
+			_, _, _, _, _, vout := bsn.Update(vin[sbid], frc.Ya[i][j], frc.Ea[i][j])
+			if bsn.Outflow >= 0 {
+				vin[bsn.Outflow] += vout
+			}
+			q[sbid][j] = vout
+		}
+	}
+	return q, nil
+}
@@ -1,12 +1,12 @@
 package swat
 
 import (
-	"log"
+	"fmt"
 	"math"
 )
 
 // Renew subbasin for resampling parameters
-func (b *SubBasin) Renew(CNf, ESCO, CHN, OVN, SURLAG, GWDELAY, ALPHABF, GWQMN float64) {
+func (b *SubBasin) Renew(CNf, ESCO, CHN, OVN, SURLAG, GWDELAY, ALPHABF, GWQMN float64) error {
 	b.surlag = SURLAG // surface water lag coefficient
 	b.dgw = GWDELAY   // the delay of soil zone percolation to aquifer [days]
 	b.aqt = GWQMN     // the threshold water level in the shallow aquifer for groundwater contribution to the main channel to occur (mmH2O) (pg.175)
@@ -32,6 +32,7 @@ func (b *SubBasin) Renew(CNf, ESCO, CHN, OVN, SURLAG, GWDELAY, ALPHABF, GWQMN fl
 
 	b.tconc = tconc(wslp, b.slplen, wovn, b.tribl, b.tribs, CHN, b.Ca)
 	if math.IsNaN(b.tconc) {
-		log.Fatalf("SubBasin.New error: tconc is NaN\n")
+		return fmt.Errorf("SubBasin.Renew error: tconc is NaN")
 	}
+	return nil
 }
@@ -1,13 +1,11 @@
 package swat
 
-import (
-	"log"
-)
+import "fmt"
 
 // topo holds subbasin topology
 var topo map[int][]int
 
-func topoOrder() []int {
+func topoOrder() ([]int, error) {
 	eval := make(map[int]bool, len(topo))
 	for i := range topo {
 		eval[i] = false
@@ -21,7 +19,7 @@ func topoOrder() []int {
 				continue
 			}
 			if _, ok := fromto[from]; ok {
-				log.Fatalf("swat.topoOrder error: subbasin %d going to %d and %d, must have only one destination\n", from, fromto[from], to)
+				return nil, fmt.Errorf("swat.topoOrder error: subbasin %d going to %d and %d, must have only one destination", from, fromto[from], to)
 			}
 			fromto[from] = to
 		}
@@ -49,13 +47,13 @@ func topoOrder() []int {
 		ord[i], ord[j] = ord[j], ord[i]
 	}
 
-	return ord
+	return ord, nil
 }
 
 // TopoToOutlet returns an ordered set of subbasin IDs leading to an outlet
-func TopoToOutlet(outlet int) []int {
+func TopoToOutlet(outlet int) ([]int, error) {
 	if _, ok := topo[outlet]; !ok {
-		log.Fatalf("swat.topoToOutlet error: no subbasin ID %d in model\n", outlet)
+		return nil, fmt.Errorf("swat.TopoToOutlet error: no subbasin ID %d in model", outlet)
 	}
 	var ord []int
 	var recur func(int)
@@ -75,5 +73,5 @@ func TopoToOutlet(outlet int) []int {
 		ord[i], ord[j] = ord[j], ord[i]
 	}
 
-	return ord
+	return ord, nil
 }
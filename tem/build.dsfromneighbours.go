@@ -1,11 +1,15 @@
 package tem
 
-import "math"
+import (
+	"fmt"
+	"math"
+)
 
-func (t *TEM) buildDsFromNeighbours(bufs map[int][]int) map[int]int {
+func (t *TEM) buildDsFromNeighbours(bufs map[int][]int) (map[int]int, error) {
 	ds := make(map[int]int, len(t.TEC))
 	f := []float64{math.Sqrt2, 1, math.Sqrt2, 1, 1, math.Sqrt2, 1, math.Sqrt2}
 	for c, tt := range t.TEC {
+		var err error
 		ds[c] = func() int { // Single direction steepest decent (D8)
 			ii := -1
 			gradmax := 0.
@@ -23,11 +27,15 @@ func (t *TEM) buildDsFromNeighbours(bufs map[int][]int) map[int]int {
 						ii = bc
 					}
 				} else {
-					panic("buildDsFromNeighbours err1")
+					err = &DEMError{bc, fmt.Sprintf("neighbour of cell %d not found in the TEM", c)}
+					return -1
 				}
 			}
 			return ii
 		}()
+		if err != nil {
+			return nil, err
+		}
 	}
-	return ds
+	return ds, nil
 }
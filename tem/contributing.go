@@ -15,7 +15,7 @@ func (t *TEM) ContributingAreaIDs(cid0 int) []int {
 
 // DownslopeContributingAreaIDs returns a list of upslope cell IDs that make up the contributing area to cid0,
 // yet ordered in the downslope (topologically-safe) direction. cid0 < 0 returns entire TEM
-func (t *TEM) DownslopeContributingAreaIDs(cid0 int) ([]int, map[int]int) {
+func (t *TEM) DownslopeContributingAreaIDs(cid0 int) ([]int, map[int]int, error) {
 	queue := list.New()
 	eval := make(map[int]bool, len(t.TEC))
 	proceed := func(cid int) bool {
@@ -29,7 +29,10 @@ func (t *TEM) DownslopeContributingAreaIDs(cid0 int) ([]int, map[int]int) {
 		return true
 	}
 
-	dsa := t.Downslopes() // from{to}
+	dsa, err := t.Downslopes() // from{to}
+	if err != nil {
+		return nil, nil, err
+	}
 	c, ds, i := make([]int, len(t.TEC)), make(map[int]int), 0
 	for _, k := range t.Peaks(cid0) {
 		queue.PushBack(k) // initial enqueue
@@ -49,7 +52,7 @@ func (t *TEM) DownslopeContributingAreaIDs(cid0 int) ([]int, map[int]int) {
 		i++
 	}
 	if cid0 < 0 {
-		return c, ds
+		return c, ds, nil
 	}
 	c[i] = cid0
 	ds[cid0] = -1
@@ -70,7 +73,7 @@ func (t *TEM) DownslopeContributingAreaIDs(cid0 int) ([]int, map[int]int) {
 	// 	cktopo[i] = true
 	// }
 
-	return u, ds
+	return u, ds, nil
 }
 
 // UnitContributingArea computes the (unit) contributing area (count) to a given cell id
@@ -79,8 +82,11 @@ func (t *TEM) UnitContributingArea(cid int) int {
 }
 
 // ContributingCellMap returns a map of upslope TEC count for every TEC in TEM cascading to cellID cid0.
-func (t *TEM) ContributingCellMap(cid0 int) map[int]int {
-	o, m := t.DownslopeContributingAreaIDs(cid0)
+func (t *TEM) ContributingCellMap(cid0 int) (map[int]int, error) {
+	o, m, err := t.DownslopeContributingAreaIDs(cid0)
+	if err != nil {
+		return nil, err
+	}
 	mcnt := make(map[int]int, len(o))
 	for _, c := range o {
 		mcnt[c] = 1
@@ -92,7 +98,7 @@ func (t *TEM) ContributingCellMap(cid0 int) map[int]int {
 			}
 		}
 	}
-	return mcnt
+	return mcnt, nil
 }
 
 // ContributingCellCounts returns a map of upslope TEC count for every TEC in TEM
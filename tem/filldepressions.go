@@ -3,6 +3,7 @@ package tem
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
 	"os"
 
 	"github.com/maseology/goHydro/grid"
@@ -11,7 +12,17 @@ import (
 
 const small = 1e-11 // "have ~15 significant digits"; using: 1e-11 decimals allow 4-digit elvation <9999 in 64-bit floats
 
-func (t *TEM) FillDepressions(gd *grid.Definition, fixflats bool, fprfx string) {
+// DEMError reports a cell of a TEM inconsistent with its grid definition or drainage topology
+type DEMError struct {
+	Cell int
+	Msg  string
+}
+
+func (e *DEMError) Error() string { return fmt.Sprintf("tem: cell %d: %s", e.Cell, e.Msg) }
+
+// FillDepressions removes the pits of the TEM, optionally fixing flat regions;
+// returns a *DEMError when the TEM and grid definition gd do not conform
+func (t *TEM) FillDepressions(gd *grid.Definition, fixflats bool, fprfx string) error {
 	// ref: Wang, L., H. Liu, 2006. An efficient method for identifying and filling surface depressions in digital elevation models for hydrologic analysis and modelling. International Journal of Geographical Information Science 20(2): 193-213.
 	// NOTE: Zhou etal. (2016) is supposed to be faster than Wang and Liu (2006) but doesn't appear to be the case as coded here.
 	println("  building priority queue")
@@ -20,7 +31,7 @@ func (t *TEM) FillDepressions(gd *grid.Definition, fixflats bool, fprfx string)
 	bufs := gd.Buffers(false, true)
 	for _, c := range t.Outlets() {
 		if _, ok := bufs[c]; !ok {
-			panic("FillDepressions err2.1")
+			return &DEMError{c, "outlet not found in grid definition"}
 		}
 		if func() bool { // edge detection
 			for _, c := range bufs[c] {
@@ -34,7 +45,7 @@ func (t *TEM) FillDepressions(gd *grid.Definition, fixflats bool, fprfx string)
 				zs[c] = t.Z
 				pq.Push(c, zs[c])
 			} else {
-				panic("FillDepressions err2.2")
+				return &DEMError{c, "outlet not found in the TEM"}
 			}
 		}
 	}
@@ -45,14 +56,14 @@ func (t *TEM) FillDepressions(gd *grid.Definition, fixflats bool, fprfx string)
 	for pq.Len() > 0 {
 		ic, err := pq.Pop()
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("tem.FillDepressions: %v", err)
 		}
 		c := ic.(int)
 		cnt++
 		pqcnt[c] = cnt
 
 		if _, ok := bufs[c]; !ok {
-			panic("FillDepressions err3")
+			return &DEMError{c, "cell not found in grid definition"}
 		}
 		for _, bc := range bufs[c] {
 			if bc < 0 || bc == c {
@@ -72,19 +83,24 @@ func (t *TEM) FillDepressions(gd *grid.Definition, fixflats bool, fprfx string)
 					}
 					pq.Push(bc, tc.Z) // zs[bc])
 				} else {
-					panic("FillDepressions err4")
+					return &DEMError{bc, fmt.Sprintf("neighbour of cell %d not found in the TEM", c)}
 				}
 			}
 		}
 	}
 
 	if len(fprfx) > 0 {
-		writeInts(fprfx+"iflat0.indx", pqcnt, gd.Ncells())
+		if err := writeInts(fprfx+"iflat0.indx", pqcnt, gd.Ncells()); err != nil {
+			return err
+		}
 	}
 
 	if fixflats {
 		println("  fixing flat regions..")
-		zs = fixflatregions(gd, zs, flat, bufs, fprfx) // I don't see much improvement
+		var err error
+		if zs, err = fixflatregions(gd, zs, flat, bufs, fprfx); err != nil { // I don't see much improvement
+			return err
+		}
 	}
 
 	println("  re-building flowpaths")
@@ -93,13 +109,18 @@ func (t *TEM) FillDepressions(gd *grid.Definition, fixflats bool, fprfx string)
 			tt.Z = z
 			t.TEC[c] = tt
 		} else {
-			panic("FillDepressions.buildflowpaths err5")
+			return &DEMError{c, "filled cell not found in the TEM"}
 		}
 	}
-	t.buildUpslopes(t.buildDsFromNeighbours(bufs))
+	ds, err := t.buildDsFromNeighbours(bufs)
+	if err != nil {
+		return err
+	}
+	t.buildUpslopes(ds)
+	return nil
 }
 
-func fixflatregions(gd *grid.Definition, zs, flat map[int]float64, bufs map[int][]int, fprfx string) map[int]float64 {
+func fixflatregions(gd *grid.Definition, zs, flat map[int]float64, bufs map[int][]int, fprfx string) (map[int]float64, error) {
 	// after: Garbrecht Martz 1997 The assignment of drainage direction over flat surfaces in raster digital elevation models
 	crwl := gd.ToCrawler(false)
 	println("    locating flat regions")
@@ -177,17 +198,19 @@ func fixflatregions(gd *grid.Definition, zs, flat map[int]float64, bufs map[int]
 				}
 			}
 			if len(bouts) == 0 {
-				func() {
+				if !func() bool {
 					for _, c := range aflat {
 						for _, bc := range bufs[c] {
 							if bc < 0 {
 								aouts[c]++ // flat region draining to farfield
-								return
+								return true
 							}
 						}
 					}
-					panic("filldrepressions flat regaion farfield expected")
-				}()
+					return false
+				}() {
+					return nil, &DEMError{aflat[0], "flat region without outlet, expected to drain to the farfield"}
+				}
 			} else {
 				for bout := range bouts {
 					for _, bc := range bufs[bout] {
@@ -199,7 +222,7 @@ func fixflatregions(gd *grid.Definition, zs, flat map[int]float64, bufs map[int]
 			}
 
 			// Step 1: gradient towards lower terrain
-			func() {
+			if err := func() error {
 				// print(" - step1")
 				q := make([]int, 0, len(aouts))
 				alev := make(map[int]int)
@@ -211,7 +234,7 @@ func fixflatregions(gd *grid.Definition, zs, flat map[int]float64, bufs map[int]
 					a := q[0]
 					q = q[1:]
 					if _, ok := alev[a]; !ok {
-						panic("fixflatregions step 1 err")
+						return &DEMError{a, "queued flat cell not levelled towards lower terrain"}
 					}
 					for _, bc := range bufs[a] {
 						if _, ok := ma[bc]; ok {
@@ -230,10 +253,13 @@ func fixflatregions(gd *grid.Definition, zs, flat map[int]float64, bufs map[int]
 				for c, v := range alev {
 					olev1[c] = v
 				}
-			}()
+				return nil
+			}(); err != nil {
+				return nil, err
+			}
 
 			// Step 2: gradient away from higher terrain
-			func() {
+			if err := func() error {
 				// print(" - step2")
 				blev, aq := make(map[int]int), []int{}
 				for _, bb := range b {
@@ -248,7 +274,7 @@ func fixflatregions(gd *grid.Definition, zs, flat map[int]float64, bufs map[int]
 					b := aq[0]
 					aq = aq[1:]
 					if _, ok := blev[b]; !ok {
-						panic("fixflatregions step 2 err")
+						return &DEMError{b, "queued flat cell not levelled away from higher terrain"}
 					}
 					for _, bc := range bufs[b] {
 						if _, ok := ma[bc]; ok {
@@ -278,10 +304,13 @@ func fixflatregions(gd *grid.Definition, zs, flat map[int]float64, bufs map[int]
 						olev2[c] = 0 // flat area outlet cells are excluded
 					}
 				}
-			}()
+				return nil
+			}(); err != nil {
+				return nil, err
+			}
 			// println(" - complete")
 		} else {
-			panic("fixflatregions err2")
+			return nil, &DEMError{aflat[0], "flat region boundary not found"}
 		}
 	}
 
@@ -306,17 +335,17 @@ func fixflatregions(gd *grid.Definition, zs, flat map[int]float64, bufs map[int]
 
 	// Print outputs for testing
 	if len(fprfx) > 0 {
-		writeInts(fprfx+"iflat.indx", iflat, gd.Ncells())
-		writeInts(fprfx+"ibrd.indx", ibrd, gd.Ncells())
-		writeInts(fprfx+"olev1.indx", olev1, gd.Ncells())
-		writeInts(fprfx+"olev2.indx", olev2, gd.Ncells())
-		writeInts(fprfx+"olev3.indx", olev3, gd.Ncells())
+		for fp, m := range map[string]map[int]int{"iflat": iflat, "ibrd": ibrd, "olev1": olev1, "olev2": olev2, "olev3": olev3} {
+			if err := writeInts(fprfx+fp+".indx", m, gd.Ncells()); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	return zs
+	return zs, nil
 }
 
-func writeInts(fp string, m map[int]int, nc int) {
+func writeInts(fp string, m map[int]int, nc int) error {
 	i32 := make([]int32, nc)
 	for c := 0; c < nc; c++ {
 		if v, ok := m[c]; ok {
@@ -327,11 +356,12 @@ func writeInts(fp string, m map[int]int, nc int) {
 	}
 	buf := new(bytes.Buffer)
 	if err := binary.Write(buf, binary.LittleEndian, i32); err != nil {
-		panic(err)
+		return fmt.Errorf("tem.writeInts: %v", err)
 	}
 	if err := os.WriteFile(fp, buf.Bytes(), 0644); err != nil { // see: https://en.wikipedia.org/wiki/File_system_permissions
-		panic(err)
+		return fmt.Errorf("tem.writeInts: %v", err)
 	}
+	return nil
 }
 
 // func writeFloats(fp string, m map[int]float64, nc int) {
@@ -25,7 +25,10 @@ func NewFromReal(r grid.Real) (*TEM, error) {
 	// 	t.TEC[c] = TEC{Z: z, G: g, A: a}
 	// }
 
-	ds := t.buildDsFromNeighbours(bufs)
+	ds, err := t.buildDsFromNeighbours(bufs)
+	if err != nil {
+		return nil, err
+	}
 	// t.checkVals()
 	t.buildUpslopes(ds)
 
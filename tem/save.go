@@ -1,7 +1,7 @@
 package tem
 
 import (
-	"log"
+	"fmt"
 
 	"github.com/maseology/goHydro/grid"
 	"github.com/maseology/mmio"
@@ -33,7 +33,7 @@ func (t *TEM) SaveUHDEM(fp string, gd *grid.Definition) error {
 	for i, a := range t.USlp {
 		for _, c := range a {
 			if _, ok := mc[c]; !ok {
-				log.Fatalf("SaveUHDEM error, TEC ID [%d] (upslope of %d) not found in model\n", c, i)
+				return &DEMError{c, fmt.Sprintf("upslope of %d, not found in the TEM", i)}
 			}
 			mc[c] = false
 			m = append(m, ft{c, i})
@@ -1,6 +1,6 @@
 package tem
 
-import "log"
+import "fmt"
 
 // Outlets returns cells that flow to farfield
 func (t *TEM) Outlets() []int {
@@ -90,15 +90,17 @@ func (t *TEM) SubSet(fromid int) (*TEM, []int) {
 	return &TEM{TEC: tss, USlp: uss}, uids
 }
 
-func (t *TEM) Downslopes() map[int]int {
+// Downslopes returns the downslope cell of every cell draining to another;
+// returns a *DEMError when a cell drains to more than one cell
+func (t *TEM) Downslopes() (map[int]int, error) {
 	ds := make(map[int]int, len(t.USlp))
 	for to, v := range t.USlp {
 		for _, from := range v {
-			if _, ok := ds[from]; ok {
-				log.Fatalln(" TEM.downslopes() error: expecting a tree graph")
+			if d, ok := ds[from]; ok {
+				return nil, &DEMError{from, fmt.Sprintf("drains to cells %d and %d, expecting a tree graph", d, to)}
 			}
 			ds[from] = to
 		}
 	}
-	return ds // from{to}
+	return ds, nil // from{to}
 }
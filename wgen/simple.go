@@ -2,22 +2,33 @@ package wgen
 
 import (
 	"math/rand"
-	"time"
+
+	"github.com/maseology/goHydro/internal/rngseed"
 )
 
 type WGEN struct {
 	WetDry [][]float64
+	Seed   int64 // seed applied by NewSeed
 	rng    *rand.Rand
 }
 
-// src = mrg63k3a.New() or src = rand.NewSource(seed)
+// New WGEN constructor: src = mrg63k3a.New() or src = rand.NewSource(seed);
+// the source is used as given, seeding is left to the caller
 func New(src rand.Source) *WGEN {
 	var w WGEN
 	w.rng = rand.New(src)
-	w.rng.Seed(time.Now().UnixNano())
 	return &w
 }
 
+// NewSeed returns a WGEN using src seeded by seed, or the clock when zero (recorded in WGEN.Seed)
+func NewSeed(src rand.Source, seed int64) *WGEN {
+	seed = rngseed.Clock(seed)
+	w := New(src)
+	w.rng.Seed(seed)
+	w.Seed = seed
+	return w
+}
+
 func (w *WGEN) Generate(last float64) float64 {
 	//              |  dry  |  wet  |
 	// ------------ | ----- | ----- |